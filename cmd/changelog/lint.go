@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/golistic/xgo/git"
+)
+
+// lint implements the lint command checking commit messages. It returns the
+// exit code: exitOK when all messages are valid, and exitError when problems
+// were found or the messages could not be checked.
+//
+// Usage as Git commit-msg hook, which gets the path to the message as argument:
+//
+//	changelog lint "$1"
+func (c *cli) lint(args []string) int {
+
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(flags.Output(), "Usage: changelog lint [flags] [FILE]")
+		_, _ = fmt.Fprintln(flags.Output(), "\nChecks the commit message stored in FILE (standard input when - or"+
+			" omitted), or the commits in a range.\n\nFlags:")
+		flags.PrintDefaults()
+	}
+
+	var revRange string
+	flags.StringVar(&revRange, "range", "", "Check the commits in the range FROM..TO (e.g. v1.2.0..HEAD) instead of a message")
+
+	var configFile string
+	flags.StringVar(&configFile, "config", "", "Changelog configuration file (default: .changelog.yaml, .changelog.yml, or .changelog.json when found)")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	cfg := git.DefaultChangelogConfig()
+	if configFile == "" {
+		configFile, _ = git.FindChangelogConfig(".")
+	}
+	if configFile != "" {
+		var err error
+		if cfg, err = git.LoadChangelogConfig(configFile); err != nil {
+			return c.fail("loading configuration", err)
+		}
+	}
+
+	type message struct {
+		name string
+		text string
+	}
+
+	var messages []message
+
+	switch {
+	case revRange != "":
+		from, to, ok := strings.Cut(revRange, "..")
+		if !ok {
+			to = "HEAD"
+		}
+		commits, err := openRepository().Log(from, to)
+		if err != nil {
+			return c.fail("reading commits", err)
+		}
+		// report oldest first, in order they were committed
+		for i := len(commits) - 1; i >= 0; i-- {
+			text := commits[i].RawMessage
+			if text == "" {
+				text = commits[i].Message()
+			}
+			messages = append(messages, message{name: commits[i].ShortHash(), text: text})
+		}
+	default:
+		name := flags.Arg(0)
+		var data []byte
+		var err error
+		if name == "" || name == "-" {
+			name = "<stdin>"
+			data, err = io.ReadAll(c.stdin)
+		} else {
+			data, err = os.ReadFile(name)
+		}
+		if err != nil {
+			return c.fail("reading commit message", err)
+		}
+		messages = append(messages, message{name: name, text: string(data)})
+	}
+
+	code := exitOK
+	for _, msg := range messages {
+		for _, p := range git.LintMessage(msg.text, cfg.LintConfig()) {
+			_, _ = fmt.Fprintf(c.stdout, "%s:%s\n", msg.name, p)
+			code = exitError
+		}
+	}
+
+	return code
+}
@@ -1,62 +1,614 @@
 /*
- * Copyright (c) 2024, 2025, Geert JM Vanderkelen
+ * Copyright (c) 2024, 2026, Geert JM Vanderkelen
  */
 
+// Command changelog calculates the next version and generates the changelog
+// of a Git repository using Conventional Commits.
+//
+// Usage:
+//
+//	changelog [command] [flags]
+//
+// The commands are:
+//
+//	next     show the next version (tag)
+//	render   show the changelog of the next release (default)
+//	write    insert the changelog of the next release in CHANGELOG.md
+//	tag      create the annotated tag of the next release
+//	lint     check commit messages
+//
+// Errors are reported on standard error. The exit code is 0 on success,
+// 1 when the command failed (or lint found problems), and 2 when the
+// command line is invalid.
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/golistic/xgo/git"
 )
 
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+type command struct {
+	run     func(c *cli, args []string) int
+	summary string
+}
+
+var commands = map[string]command{
+	"next":   {(*cli).next, "show the next version (tag)"},
+	"render": {(*cli).render, "show the changelog of the next release (default)"},
+	"write":  {(*cli).write, "insert the changelog of the next release in CHANGELOG.md"},
+	"tag":    {(*cli).tag, "create the annotated tag of the next release"},
+	"lint":   {(*cli).lint, "check commit messages"},
+}
+
+// repository is a Git repository in which tags can be created.
+type repository interface {
+	git.Repository
+	CreateTag(name, rev, message string) error
+}
+
+// openRepository returns the repository found in the current working
+// directory. Tests replace it, for example, with a git.MemoryRepository.
+var openRepository = func() repository {
+
+	return git.NewExecRepository("")
+}
+
+// cli holds where commands read input and write output.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
 func main() {
-	var hotfix bool
-	flag.BoolVar(&hotfix, "hotfix", false, "Calculate the next PATCH version instead of MINOR")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command found in args, or render when no command
+// is provided, writing output to stdout and errors to stderr. It returns
+// the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+
+	c := &cli{stdin: os.Stdin, stdout: stdout, stderr: stderr}
+
+	name := "render"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" {
+		c.usage()
+		return exitOK
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		_, _ = fmt.Fprintf(c.stderr, "Error: unknown command %q\n\n", name)
+		c.usage()
+		return exitUsage
+	}
+
+	return cmd.run(c, args)
+}
+
+func (c *cli) usage() {
+
+	_, _ = fmt.Fprintln(c.stderr, "Usage: changelog [command] [flags]\n\nCommands:")
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		_, _ = fmt.Fprintf(c.stderr, "  %-8s %s\n", name, commands[name].summary)
+	}
+	_, _ = fmt.Fprintln(c.stderr, "\nUse \"changelog <command> -h\" for the flags of a command.")
+}
+
+// fail reports err on standard error and returns exitError.
+func (c *cli) fail(context string, err error) int {
+
+	_, _ = fmt.Fprintf(c.stderr, "Error %s: %s\n", context, err)
+	return exitError
+}
+
+// options holds the flags shared by commands.
+type options struct {
+	*cli
+	flags *flag.FlagSet
+
+	hotfix     bool
+	bump       string
+	prerelease string
+	promote    bool
+	build      string
+
+	skipTypes  string
+	skipScopes string
+	date       string
+	configFile string
+	from       string
+	to         string
+
+	tagBranch string
+	module    string
+}
+
+// newOptions returns the options of command name, registering the flags
+// for selecting the tag branch and module.
+func (c *cli) newOptions(name string) *options {
+
+	o := &options{
+		cli:   c,
+		flags: flag.NewFlagSet(name, flag.ContinueOnError),
+	}
+	o.flags.SetOutput(c.stderr)
+
+	o.flags.Usage = func() {
+		_, _ = fmt.Fprintf(o.flags.Output(), "Usage: changelog %s [flags]\n\nFlags:\n", name)
+		o.flags.PrintDefaults()
+	}
+
+	o.flags.StringVar(&o.tagBranch, "tag-branch", "main", "Branch to search for the latest tag")
+	o.flags.StringVar(&o.module, "module", "", "Directory of the module, relative to the repository root, using tags prefixed with it (e.g. ./xgrpc)")
+
+	return o
+}
+
+// versionFlags registers the flags defining how the next version is calculated.
+func (o *options) versionFlags() {
+
+	o.flags.BoolVar(&o.hotfix, "hotfix", false, "Calculate the next PATCH version instead of MINOR (same as -bump=patch)")
+	o.flags.StringVar(&o.bump, "bump", "minor", "Version component to increment: auto, major, minor, or patch")
+	o.flags.StringVar(&o.prerelease, "prerelease", "", "Prerelease identifier (e.g. rc, beta); continues or starts a prerelease series")
+	o.flags.BoolVar(&o.promote, "promote", false, "Promote the latest prerelease to its final version")
+	o.flags.StringVar(&o.build, "build", "", "Build metadata appended to the version (e.g. build.42)")
+}
+
+// changelogFlags registers the flags defining how the changelog is generated.
+func (o *options) changelogFlags() {
+
+	o.flags.StringVar(&o.skipTypes, "skip-types", "", "Comma-separated list of types (feat, fix, etc.) to skip")
+	o.flags.StringVar(&o.skipScopes, "skip-scopes", "", "Comma-separated list of scopes to skip")
+	o.flags.StringVar(&o.date, "date", "", "Release date as YYYY-MM-DD (default: today)")
+	o.flags.StringVar(&o.configFile, "config", "", "Changelog configuration file (default: .changelog.yaml, .changelog.yml, or .changelog.json when found)")
+}
+
+// rangeFlags registers the flags selecting the commits of a historical release.
+func (o *options) rangeFlags() {
+
+	o.flags.StringVar(&o.from, "from", "", "Document the commits since this revision (default: tag preceding -to)")
+	o.flags.StringVar(&o.to, "to", "", "Document the commits up to this revision, for example a historical release tag")
+}
+
+// parse parses args, and returns the exit code when the command must
+// stop, for example, when -h was used.
+func (o *options) parse(args []string) (int, bool) {
+
+	if err := o.flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+
+	if o.flags.NArg() > 0 {
+		_, _ = fmt.Fprintf(o.stderr, "Error: unexpected arguments: %s\n", strings.Join(o.flags.Args(), " "))
+		return exitUsage, false
+	}
+
+	return exitOK, true
+}
+
+// repository returns the repository found in the current working
+// directory, limited to the module when provided.
+func (o *options) repository() (repository, git.Repository) {
+
+	repo := openRepository()
+	return repo, git.NewModuleRepository(repo, o.module)
+}
+
+// versionOptions returns how the next version is calculated.
+func (o *options) versionOptions() (git.VersionOptions, error) {
+
+	bump, err := git.ParseBump(o.bump)
+	if err != nil {
+		return git.VersionOptions{}, err
+	}
+	if o.hotfix {
+		bump = git.BumpPatch
+	}
+
+	return git.VersionOptions{
+		Bump:       bump,
+		Prerelease: o.prerelease,
+		Build:      o.build,
+		Promote:    o.promote,
+	}, nil
+}
+
+// config returns the changelog configuration. When no file is provided,
+// the configuration of the module is used, or, when not found, the one
+// in the current working directory.
+func (o *options) config() (git.ChangelogConfig, error) {
+
+	cfg := git.DefaultChangelogConfig()
+
+	path := o.configFile
+	if path == "" {
+		var ok bool
+		if path, ok = git.FindChangelogConfig(o.module); !ok {
+			path, _ = git.FindChangelogConfig(".")
+		}
+	}
+	if path != "" {
+		var err error
+		if cfg, err = git.LoadChangelogConfig(path); err != nil {
+			return git.ChangelogConfig{}, err
+		}
+	}
+
+	if o.skipTypes != "" {
+		cfg.SkipTypes = append(cfg.SkipTypes, strings.Split(o.skipTypes, ",")...)
+	}
+
+	if o.skipScopes != "" {
+		cfg.SkipScopes = append(cfg.SkipScopes, strings.Split(o.skipScopes, ",")...)
+	}
+
+	if o.date != "" {
+		var err error
+		if cfg.Date, err = time.Parse(time.DateOnly, o.date); err != nil {
+			return git.ChangelogConfig{}, fmt.Errorf("invalid release date: %w", err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// release returns the release documenting the commits selected using
+// -from and -to, or, when not provided, the next release.
+func (o *options) release() (git.Release, int) {
+
+	versionOpts, err := o.versionOptions()
+	if err != nil {
+		_, _ = fmt.Fprintln(o.stderr, "Error:", err)
+		return git.Release{}, exitUsage
+	}
+
+	cfg, err := o.config()
+	if err != nil {
+		return git.Release{}, o.fail("loading configuration", err)
+	}
+
+	_, repo := o.repository()
+
+	var release git.Release
+	if o.from != "" || o.to != "" {
+		release, err = git.ReleaseBetween(repo, o.from, o.to, cfg)
+	} else {
+		release, err = git.GenerateRelease(repo, o.tagBranch, versionOpts, cfg)
+	}
+	if err != nil {
+		return git.Release{}, o.fail("generating changelog", err)
+	}
+
+	return release, exitOK
+}
+
+// next implements the next command showing the next version.
+func (c *cli) next(args []string) int {
+
+	o := c.newOptions("next")
+	o.versionFlags()
+
+	var asJSON bool
+	o.flags.BoolVar(&asJSON, "json", false, `Show the version as JSON object, for example {"version": "v1.2.0"}`)
+
+	if code, ok := o.parse(args); !ok {
+		return code
+	}
+
+	return showNext(o, asJSON)
+}
+
+// showNext shows the next version, optionally as JSON object.
+func showNext(o *options, asJSON bool) int {
+
+	versionOpts, err := o.versionOptions()
+	if err != nil {
+		_, _ = fmt.Fprintln(o.stderr, "Error:", err)
+		return exitUsage
+	}
+
+	_, repo := o.repository()
+
+	tag, err := git.NextTag(repo, o.tagBranch, versionOpts)
+	if err != nil {
+		return o.fail("calculating next tag", err)
+	}
+
+	if asJSON {
+		out, _ := json.Marshal(struct {
+			Version string `json:"version"`
+		}{tag})
+		_, _ = fmt.Fprintln(o.stdout, string(out))
+		return exitOK
+	}
+
+	_, _ = fmt.Fprintln(o.stdout, tag)
+	return exitOK
+}
+
+// render implements the render command showing the changelog of the next
+// release. For compatibility, the flags -tag-only and -write of earlier
+// versions are accepted, working like the next and write commands.
+func (c *cli) render(args []string) int {
+
+	o := c.newOptions("render")
+	o.versionFlags()
+	o.changelogFlags()
+	o.rangeFlags()
+
+	var format string
+	o.flags.StringVar(&format, "format", "markdown", "Output format: "+strings.Join(git.RendererFormats, ", "))
+
+	var asJSON bool
+	o.flags.BoolVar(&asJSON, "json", false, "Show the release as JSON with version, sections, and entries (same as -format=json); an array with -all")
+
+	var all bool
+	o.flags.BoolVar(&all, "all", false, "Show all releases using the full tag history")
 
 	var tagOnly bool
-	flag.BoolVar(&tagOnly, "tag-only", false, "Only show the tag/version instead of the full changelog")
+	o.flags.BoolVar(&tagOnly, "tag-only", false, "Deprecated: use the next command")
+
+	var writeFile string
+	o.flags.StringVar(&writeFile, "write", "", "Deprecated: use the write command")
+
+	if code, ok := o.parse(args); !ok {
+		return code
+	}
+
+	switch {
+	case tagOnly:
+		return showNext(o, asJSON)
+	case writeFile != "":
+		return writeRelease(o, writeFile, all)
+	}
+
+	if asJSON {
+		format = "json"
+	}
 
-	var flagSkipTypes string
-	flag.StringVar(&flagSkipTypes, "skip-types", "", "Comma-separated list of types (feat, fix, etc.) to skip")
+	renderer, err := git.NewRenderer(format)
+	if err != nil {
+		_, _ = fmt.Fprintln(o.stderr, "Error:", err)
+		return exitUsage
+	}
 
-	var flagSkipScopes string
-	flag.StringVar(&flagSkipScopes, "skip-scopes", "", "Comma-separated list of scopes to skip")
+	var releases []git.Release
 
-	var tagBranch string
-	flag.StringVar(&tagBranch, "tag-branch", "main", "Branch to search for the latest tag (default: main)")
+	if all {
+		cfg, err := o.config()
+		if err != nil {
+			return o.fail("loading configuration", err)
+		}
 
-	flag.Parse()
+		_, repo := o.repository()
+		if releases, err = git.ReleaseHistory(repo, cfg); err != nil {
+			return o.fail("generating changelog", err)
+		}
+	} else {
+		release, code := o.release()
+		if code != exitOK {
+			return code
+		}
 
-	var skipTypes []string
-	if flagSkipTypes != "" {
-		skipTypes = strings.Split(flagSkipTypes, ",")
+		if release.IsEmpty() {
+			_, _ = fmt.Fprintln(o.stderr, "No changes detected.")
+			return exitOK
+		}
+		releases = append(releases, release)
+	}
+
+	// all releases must form a single JSON document
+	if r, ok := renderer.(git.JSONRenderer); ok && all {
+		if err := r.RenderAll(c.stdout, releases); err != nil {
+			return o.fail("rendering changelog", err)
+		}
+		return exitOK
 	}
 
-	var skipScopes []string
-	if flagSkipScopes != "" {
-		skipScopes = strings.Split(flagSkipScopes, ",")
+	for _, release := range releases {
+		if err := renderer.Render(c.stdout, release); err != nil {
+			return o.fail("rendering changelog", err)
+		}
 	}
 
-	if tagOnly {
-		tag, err := git.NextTag(tagBranch, hotfix)
+	return exitOK
+}
+
+// write implements the write command inserting the changelog of the next
+// release in a Keep a Changelog file.
+func (c *cli) write(args []string) int {
+
+	o := c.newOptions("write")
+	o.versionFlags()
+	o.changelogFlags()
+	o.rangeFlags()
+
+	var file string
+	o.flags.StringVar(&file, "file", "CHANGELOG.md", "Keep a Changelog file in which the release is inserted")
+
+	var all bool
+	o.flags.BoolVar(&all, "all", false, "Rebuild the file using the full tag history")
+
+	if code, ok := o.parse(args); !ok {
+		return code
+	}
+
+	return writeRelease(o, file, all)
+}
+
+// writeRelease inserts the release in file, or, with all, rebuilds file
+// using all releases.
+func writeRelease(o *options, file string, all bool) int {
+
+	if all {
+		cfg, err := o.config()
+		if err != nil {
+			return o.fail("loading configuration", err)
+		}
+
+		_, repo := o.repository()
+		releases, err := git.ReleaseHistory(repo, cfg)
 		if err != nil {
-			fmt.Println("Error calculating next tag:", err)
-			return
+			return o.fail("generating changelog", err)
 		}
-		if tag != "" {
-			println(tag)
+
+		if err := rebuildChangelog(file, releases); err != nil {
+			return o.fail("writing changelog", err)
 		}
-		return
+		return exitOK
 	}
 
-	if out, err := git.GenerateChangelog(tagBranch, hotfix, skipTypes, skipScopes); err != nil {
-		fmt.Println("Error generating changelog:", err)
-	} else if out != "" {
-		fmt.Print(out)
-	} else {
-		fmt.Println("No changes detected.")
+	release, code := o.release()
+	if code != exitOK {
+		return code
+	}
+
+	if release.IsEmpty() {
+		_, _ = fmt.Fprintln(o.stderr, "No changes detected.")
+		return exitOK
+	}
+
+	var section strings.Builder
+	if err := (git.MarkdownRenderer{}).Render(&section, release); err != nil {
+		return o.fail("rendering changelog", err)
+	}
+
+	if err := writeChangelog(o.stderr, file, section.String()); err != nil {
+		return o.fail("writing changelog", err)
+	}
+
+	return exitOK
+}
+
+// tag implements the tag command creating the annotated tag of the next
+// release, using the release notes as message.
+func (c *cli) tag(args []string) int {
+
+	o := c.newOptions("tag")
+	o.versionFlags()
+	o.changelogFlags()
+
+	var dryRun bool
+	o.flags.BoolVar(&dryRun, "dry-run", false, "Show the tag and its message without creating it")
+
+	if code, ok := o.parse(args); !ok {
+		return code
+	}
+
+	release, code := o.release()
+	if code != exitOK {
+		return code
+	}
+
+	if release.IsEmpty() {
+		return o.fail("creating tag", fmt.Errorf("no changes to release as %s", release.Version))
+	}
+
+	var message strings.Builder
+	message.WriteString(release.Version + "\n\n")
+	if err := (git.TextRenderer{}).Render(&message, release); err != nil {
+		return o.fail("rendering changelog", err)
+	}
+
+	if dryRun {
+		_, _ = fmt.Fprint(c.stdout, message.String())
+		return exitOK
+	}
+
+	repo, _ := o.repository()
+	if err := repo.CreateTag(release.Version, "", message.String()); err != nil {
+		return o.fail("creating tag", err)
+	}
+
+	_, _ = fmt.Fprintln(c.stdout, release.Version)
+	return exitOK
+}
+
+// writeChangelog inserts section as new release into the Keep a Changelog
+// document stored in path. The file is created when it does not exist.
+func writeChangelog(stderr io.Writer, path string, section string) error {
+
+	cf := git.NewChangelogFile()
+
+	content, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		cf = git.ParseChangelogFile(string(content))
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
 	}
+
+	added, err := cf.AddRelease(section)
+	if err != nil {
+		return err
+	}
+
+	if !added {
+		_, _ = fmt.Fprintf(stderr, "Release already documented in %s.\n", path)
+		return nil
+	}
+
+	return os.WriteFile(path, []byte(cf.String()), 0o644)
+}
+
+// rebuildChangelog replaces the releases documented in the Keep a Changelog
+// document stored in path with releases (newest first). The header, the
+// Unreleased section, and link reference definitions are kept. The file is
+// created when it does not exist.
+func rebuildChangelog(path string, releases []git.Release) error {
+
+	existing := git.NewChangelogFile()
+
+	content, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		existing = git.ParseChangelogFile(string(content))
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	cf := &git.ChangelogFile{Header: existing.Header}
+
+	for _, release := range slices.Backward(releases) {
+		var section strings.Builder
+		if err := (git.MarkdownRenderer{}).Render(&section, release); err != nil {
+			return err
+		}
+		if _, err := cf.AddRelease(section.String()); err != nil {
+			return err
+		}
+	}
+
+	if u, ok := existing.Release("Unreleased"); ok {
+		cf.Releases = append([]git.ChangelogRelease{*u}, cf.Releases...)
+	}
+	cf.Links = existing.Links
+
+	return os.WriteFile(path, []byte(cf.String()), 0o644)
 }
@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golistic/xgo/git"
+	"github.com/golistic/xgo/xt"
+)
+
+// useMemoryRepository makes the commands use a git.MemoryRepository with
+// the release v1.0.0 followed by a fix and a feature.
+func useMemoryRepository(t *testing.T) *git.MemoryRepository {
+	t.Helper()
+
+	repo := git.NewMemoryRepository()
+	repo.CommitMessage("feat: first")
+	xt.OK(t, repo.Tag("v1.0.0"))
+	repo.CommitMessage("fix: handle empty input")
+	repo.CommitMessage("feat(api): add endpoint")
+
+	prev := openRepository
+	openRepository = func() repository { return repo }
+	t.Cleanup(func() { openRepository = prev })
+
+	return repo
+}
+
+// runCommand runs the command line args, returning the exit code and
+// what was written to stdout and stderr.
+func runCommand(args ...string) (int, string, string) {
+
+	var stdout, stderr strings.Builder
+	code := run(args, &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+
+	cases := map[string]struct {
+		args   []string
+		code   int
+		stdout string
+		stderr string // regular expression
+	}{
+		"help": {
+			args:   []string{"help"},
+			code:   exitOK,
+			stderr: `^Usage: changelog \[command\]`,
+		},
+		"flags of command": {
+			args:   []string{"next", "-h"},
+			code:   exitOK,
+			stderr: `^Usage: changelog next \[flags\]`,
+		},
+		"unknown command": {
+			args:   []string{"publish"},
+			code:   exitUsage,
+			stderr: `^Error: unknown command "publish"\n\nUsage:`,
+		},
+		"unknown flag": {
+			args:   []string{"next", "-nope"},
+			code:   exitUsage,
+			stderr: `^flag provided but not defined: -nope\n`,
+		},
+		"unexpected arguments": {
+			args:   []string{"next", "v2.0.0"},
+			code:   exitUsage,
+			stderr: `^Error: unexpected arguments: v2.0.0\n$`,
+		},
+		"invalid bump": {
+			args:   []string{"next", "-bump", "huge"},
+			code:   exitUsage,
+			stderr: `^Error: .*huge`,
+		},
+		"invalid format": {
+			args:   []string{"render", "-format", "pdf"},
+			code:   exitUsage,
+			stderr: `^Error: .*pdf`,
+		},
+		"next": {
+			args:   []string{"next"},
+			code:   exitOK,
+			stdout: "v1.1.0\n",
+		},
+		"next patch": {
+			args:   []string{"next", "-bump", "patch"},
+			code:   exitOK,
+			stdout: "v1.0.1\n",
+		},
+		"next as JSON": {
+			args:   []string{"next", "-json", "-prerelease", "rc"},
+			code:   exitOK,
+			stdout: `{"version":"v1.1.0-rc.1"}` + "\n",
+		},
+		"render is default": {
+			args:   []string{"-date", "2026-10-18"},
+			code:   exitOK,
+			stdout: "## [1.1.0] - 2026-10-18\n\n### Added\n\n- **api**: add endpoint\n\n### Fixed\n\n- handle empty input\n\n",
+		},
+		"render invalid date": {
+			args:   []string{"render", "-date", "tomorrow"},
+			code:   exitError,
+			stderr: `^Error loading configuration: invalid release date`,
+		},
+		"tag dry run": {
+			args:   []string{"tag", "-dry-run", "-date", "2026-10-18"},
+			code:   exitOK,
+			stdout: "v1.1.0\n\nAdded:\n- api: add endpoint\n\nFixed:\n- handle empty input\n",
+		},
+		"lint valid message": {
+			args: []string{"lint", "testdata/valid.txt"},
+			code: exitOK,
+		},
+		"lint invalid message": {
+			args: []string{"lint", "testdata/invalid.txt"},
+			code: exitError,
+			stdout: "testdata/invalid.txt:2:1: subject must be followed by a blank line (body-leading-blank)\n" +
+				"testdata/invalid.txt:1:1: subject must follow Conventional Commits as \"type(scope): description\" (header-format)\n",
+		},
+		"lint message not found": {
+			args:   []string{"lint", "testdata/nope.txt"},
+			code:   exitError,
+			stderr: `^Error reading commit message: `,
+		},
+		"lint unknown revision": {
+			args:   []string{"lint", "-range", "v1.0.9..HEAD"},
+			code:   exitError,
+			stderr: `^Error reading commits: unknown revision "v1.0.9"\n$`,
+		},
+		"render unknown revision": {
+			args:   []string{"render", "-from", "v1.0.9"},
+			code:   exitError,
+			stderr: `^Error generating changelog: .*unknown revision "v1.0.9"\n$`,
+		},
+		"lint range": {
+			args: []string{"lint", "-range", "v1.0.0..HEAD"},
+			code: exitOK,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			useMemoryRepository(t)
+
+			code, stdout, stderr := runCommand(c.args...)
+			xt.Eq(t, c.code, code, stderr)
+			xt.Eq(t, c.stdout, stdout)
+			if c.stderr == "" {
+				xt.Eq(t, "", stderr)
+			} else {
+				xt.MatchString(t, c.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestRun_tag(t *testing.T) {
+	repo := useMemoryRepository(t)
+
+	code, stdout, stderr := runCommand("tag")
+	xt.Eq(t, exitOK, code, stderr)
+	xt.Eq(t, "v1.1.0\n", stdout)
+
+	tag, err := repo.LatestTag("main", "")
+	xt.OK(t, err)
+	xt.Eq(t, "v1.1.0", tag)
+
+	t.Run("nothing to release", func(t *testing.T) {
+		code, _, stderr := runCommand("tag")
+		xt.Eq(t, exitError, code)
+		xt.Eq(t, "Error creating tag: no changes to release as v1.2.0\n", stderr)
+	})
+}
+
+func TestRun_renderAllJSON(t *testing.T) {
+	repo := useMemoryRepository(t)
+	xt.OK(t, repo.Tag("v1.1.0"))
+
+	for _, args := range [][]string{{"render", "-all", "-json"}, {"render", "-all", "-format", "json"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			code, stdout, stderr := runCommand(args...)
+			xt.Eq(t, exitOK, code, stderr)
+
+			var releases []git.Release
+			xt.OK(t, json.Unmarshal([]byte(stdout), &releases))
+			xt.Eq(t, 2, len(releases))
+			xt.Eq(t, "v1.1.0", releases[0].Version)
+			xt.Eq(t, "v1.0.0", releases[1].Version)
+		})
+	}
+}
+
+func TestRun_write(t *testing.T) {
+	useMemoryRepository(t)
+
+	file := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+	code, _, stderr := runCommand("write", "-file", file, "-date", "2026-10-18")
+	xt.Eq(t, exitOK, code, stderr)
+
+	content, err := os.ReadFile(file)
+	xt.OK(t, err)
+	xt.Assert(t, strings.Contains(string(content), "\n## [1.1.0] - 2026-10-18\n\n### Added\n\n- **api**: add endpoint\n"))
+
+	t.Run("already documented", func(t *testing.T) {
+		code, _, stderr := runCommand("write", "-file", file, "-date", "2026-10-18")
+		xt.Eq(t, exitOK, code)
+		xt.Eq(t, "Release already documented in "+file+".\n", stderr)
+	})
+}
+
+func TestRun_lintRawMessage(t *testing.T) {
+	repo := useMemoryRepository(t)
+
+	// the parsed message looks valid; only the raw message misses the
+	// blank line after the subject
+	repo.Commit(git.Commit{
+		Hash:       "0123456789abcdef",
+		Subject:    "fix: close file",
+		Body:       "Files were left open.",
+		RawMessage: "fix: close file\nFiles were left open.\n",
+	})
+
+	code, stdout, _ := runCommand("lint", "-range", "v1.0.0..HEAD")
+	xt.Eq(t, exitError, code)
+	xt.Eq(t, "0123456:2:1: subject must be followed by a blank line (body-leading-blank)\n", stdout)
+}
@@ -1,305 +1,199 @@
 /*
- * Copyright (c) 2025, Geert JM Vanderkelen
+ * Copyright (c) 2025, 2026, Geert JM Vanderkelen
  */
 
 package git
 
 import (
-	"bytes"
 	"fmt"
-	"os/exec"
 	"regexp"
 	"slices"
-	"strconv"
 	"strings"
-	"time"
-
-	"golang.org/x/mod/semver"
 )
 
-var reConventionalCommit = regexp.MustCompile(`^(feat|fix|hotfix|docs|style|refactor|perf|test|build|ci|chore|revert)(\([a-zA-Z0-9_-]+\))?: (.*)$`)
-
-var conventionalMapping = map[string]string{
-	"feat":     "Added",
-	"fix":      "Fixed",
-	"hotfix":   "Fixed",
-	"docs":     "Changed",
-	"style":    "Changed",
-	"refactor": "Changed",
-	"perf":     "Changed",
-	"build":    "Changed",
-}
+// reConventionalCommit matches a commit subject following the Conventional
+// Commits specification. Which types are included in the changelog is
+// configured using ChangelogConfig.
+var reConventionalCommit = regexp.MustCompile(`^([a-zA-Z]+)(\([a-zA-Z0-9_-]+\))?(!)?: (.*)$`)
 
-var sectionOrder = []string{"Added", "Changed", "Fixed"}
+// sectionBreaking is the name of the section listing breaking changes.
+const sectionBreaking = "Breaking Changes"
 
-// LatestTag retrieves the most recent Git tag reachable from the given branch.
+// LatestTag retrieves the most recent Git tag reachable from the given branch
+// of the repository found in the current working directory.
 // If branch is empty, it uses "main" by default. It returns the latest tag as a string.
 func LatestTag(branch string) (string, error) {
 
-	if strings.TrimSpace(branch) == "" {
-		branch = "main"
-	}
-
-	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0", branch)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(out.String()), nil
+	return NewExecRepository("").LatestTag(branch, "")
 }
 
-// CommitsSince retrieves the list of commit messages since the specified Git tag up to HEAD.
-func CommitsSince(tag string) ([]string, error) {
-
-	cmd := exec.Command("git", "log", fmt.Sprintf("%s..HEAD", tag),
-		"--oneline",
-		"--no-decorate",
-		"--format=%s")
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	if err := cmd.Run(); err != nil {
-		return nil, err
-	}
-	commits := strings.Split(strings.TrimSpace(out.String()), "\n")
-
-	// Handle case with no commits (empty string results in [""])
-	if len(commits) == 1 && commits[0] == "" {
-		return []string{}, nil
-	}
+// CommitsSince retrieves the commits since the specified Git tag up to HEAD
+// of the repository found in the current working directory.
+// Commits are returned newest first and include the full commit message
+// parsed into subject, body, and footers.
+func CommitsSince(tag string) ([]Commit, error) {
 
-	return commits, nil
+	return NewExecRepository("").Log(tag, "HEAD")
 }
 
-type changelogEntry struct {
-	scope   string
-	message string
-}
+// RenderChangelog processes a list of commits and organizes them into
+// categorized changelog sections as configured by cfg, and returns it
+// as Markdown. The tag parameter is the next version tag to include in
+// the heading, together with cfg.Date (today when zero).
+//
+// See NewRelease for how commits are organized.
+func RenderChangelog(tag string, commits []Commit, cfg ChangelogConfig) string {
 
-type changelogSection struct {
-	name    string
-	entries map[string][]changelogEntry
-}
+	var changelog strings.Builder
 
-func newChangelogSection(name string) *changelogSection {
+	// rendering Markdown into strings.Builder does not fail
+	_ = MarkdownRenderer{}.Render(&changelog, NewRelease(tag, cfg.releaseDate(), commits, cfg))
 
-	return &changelogSection{
-		name:    name,
-		entries: make(map[string][]changelogEntry),
-	}
+	return changelog.String()
 }
 
-func (s *changelogSection) addEntry(name, message string) {
-
-	if s.entries == nil {
-		s.entries = make(map[string][]changelogEntry)
-	}
+// GenerateChangelog is a high-level helper that orchestrates generating the
+// changelog text based on common inputs you would pass via CLI flags.
+//
+// It performs the following steps using repo:
+//  1. Determine the latest tag reachable from the given branch.
+//  2. Collect commits since the latest tag.
+//  3. Compute the next version using opts (see NextVersionWith); with BumpAuto
+//     the increment is derived from the collected commits (see ResolveBump).
+//  4. Render the changelog.
+//
+// Parameters:
+//   - repo: the Git repository, for example, NewExecRepository("")
+//   - tagBranch: branch on which to search the latest tag (defaults to "main" if empty)
+//   - opts: how to calculate the next version, including prerelease and build metadata
+//   - cfg: how commits are organized in sections, and which types and scopes to omit
+//
+// Returns the rendered changelog as Markdown, or an error. Use GenerateRelease
+// to render using another Renderer.
+func GenerateChangelog(repo Repository, tagBranch string, opts VersionOptions, cfg ChangelogConfig) (string, error) {
 
-	if slices.ContainsFunc(s.entries[name], func(e changelogEntry) bool {
-		return strings.Contains(e.message, message)
-	}) {
-		return
+	release, err := GenerateRelease(repo, tagBranch, opts, cfg)
+	if err != nil {
+		return "", err
 	}
-	s.entries[name] = append(s.entries[name], changelogEntry{
-		scope:   name,
-		message: message,
-	})
-}
-
-// RenderChangelog processes a list of commit messages and organizes them into
-// categorized changelog sections. The tag parameter is the next version tag to
-// include in the heading. Use skipTypes to omit certain Conventional Commit
-// types and skipScopes to omit specific scopes from the output.
-func RenderChangelog(tag string, commits []string, skipTypes []string, skipScopes []string) string {
 
 	var changelog strings.Builder
+	if err := (MarkdownRenderer{}).Render(&changelog, release); err != nil {
+		return "", err
+	}
 
-	sections := map[string]*changelogSection{}
-
-	for _, commit := range commits {
-		matches := reConventionalCommit.FindStringSubmatch(commit)
-		if matches == nil {
-			continue
-		}
-
-		commitType := matches[1]
-		if slices.Contains(skipTypes, commitType) {
-			continue
-		}
+	return changelog.String(), nil
+}
 
-		commitScope := ""
-		commitMessage := ""
-
-		switch len(matches) {
-		case 4:
-			commitScope = strings.Trim(matches[2], "()")
-			commitMessage = matches[3]
-		case 3:
-			commitMessage = strings.TrimSpace(matches[2])
-		default:
-			continue
-		}
+// GenerateRelease works like GenerateChangelog, but returns the Release
+// which can be rendered using any Renderer.
+func GenerateRelease(repo Repository, tagBranch string, opts VersionOptions, cfg ChangelogConfig) (Release, error) {
 
-		if header, exists := conventionalMapping[commitType]; exists {
-			s, ok := sections[header]
-			if !ok {
-				s = newChangelogSection(header)
-				sections[header] = s
-			}
-			s.addEntry(commitScope, strings.TrimSpace(commitMessage))
-		}
+	latestTag, err := repo.LatestTag(tagBranch, "")
+	if err != nil {
+		return Release{}, fmt.Errorf("latest tag: %w", err)
 	}
 
-	changelog.WriteString(fmt.Sprintf("## [%s] - %s\n\n",
-		strings.TrimPrefix(tag, "v"), time.Now().Format(time.DateOnly)))
-
-	for _, s := range sectionOrder {
-		section, ok := sections[s]
-		if !ok || len(section.entries) == 0 {
-			continue
-		}
+	commits, err := repo.Log(latestTag, "HEAD")
+	if err != nil {
+		return Release{}, fmt.Errorf("commits since %s: %w", latestTag, err)
+	}
 
-		changelog.WriteString(fmt.Sprintf("### %s\n\n", section.name))
-
-		for scope, entries := range section.entries {
-			if slices.Contains(skipScopes, scope) {
-				continue
-			}
-			if len(entries) == 0 {
-				continue
-			}
-			if scope == "" {
-				for _, entry := range entries {
-					changelog.WriteString(fmt.Sprintf("- %s\n", entry.message))
-				}
-			} else {
-				if len(entries) == 1 {
-					changelog.WriteString(fmt.Sprintf("- **%s**: %s\n", scope, entries[0].message))
-				} else {
-					slices.Reverse(entries)
-					changelog.WriteString(fmt.Sprintf("- **%s**:\n", scope))
-					for _, entry := range entries {
-						changelog.WriteString(fmt.Sprintf("    - %s\n", entry.message))
-					}
-				}
-			}
-		}
+	if opts.Bump == BumpAuto {
+		opts.Bump = ResolveBump(latestTag, commits)
+	}
 
-		changelog.WriteString("\n")
+	nextTag, err := NextVersionWith(latestTag, opts)
+	if err != nil {
+		return Release{}, fmt.Errorf("next version: %w", err)
 	}
 
-	return changelog.String()
+	return NewRelease(nextTag, cfg.releaseDate(), commits, cfg), nil
 }
 
-// NextVersion calculates the next version using golang.org/x/mod/semver.
-// It expects a semantic version tag like 'vMAJOR.MINOR.PATCH'.
-// When hotfix is true, the PATCH component is incremented; otherwise the MINOR
-// component is incremented and PATCH reset to 0. The returned version is a
-// canonical semver starting with 'v'.
-func NextVersion(tag string, hotfix bool) (string, error) {
+// ChangelogBetween returns, as Markdown, the changelog of the commits between
+// the revisions from and to of the repository found in the current working
+// directory, for example, to regenerate the notes of a historical release.
+// See ReleaseBetween for how from and to are used.
+func ChangelogBetween(from, to string) (string, error) {
 
-	// ensure tag has the leading 'v' required by x/mod/semver
-	if !strings.HasPrefix(tag, "v") {
-		tag = "v" + tag
+	release, err := ReleaseBetween(NewExecRepository(""), from, to, DefaultChangelogConfig())
+	if err != nil {
+		return "", err
 	}
 
-	if !semver.IsValid(tag) {
-		return "", fmt.Errorf("invalid semantic version: %q", tag)
+	var changelog strings.Builder
+	if err := (MarkdownRenderer{}).Render(&changelog, release); err != nil {
+		return "", err
 	}
 
-	// normalize to canonical form
-	ver := semver.Canonical(tag)
+	return changelog.String(), nil
+}
 
-	// strip prerelease and build metadata, operate on base version only
-	if pre := semver.Prerelease(ver); pre != "" {
-		ver = strings.TrimSuffix(ver, pre)
-	}
-	if build := semver.Build(ver); build != "" {
-		ver = strings.TrimSuffix(ver, build)
-	}
+// ReleaseBetween returns the Release documenting the commits reachable from
+// revision to, but not from revision from.
+//
+// When to is a tag, it is used as version, and the release is dated using
+// the commit date of the tag (unless cfg.Date is set). When from is empty,
+// the tag preceding to is used, or, when there is none, all commits
+// reachable from to are documented. When to is not a tag, for example "HEAD",
+// the version is "Unreleased".
+func ReleaseBetween(repo Repository, from, to string, cfg ChangelogConfig) (Release, error) {
 
-	// extract numeric parts
-	core := strings.TrimPrefix(ver, "v")
-	parts := strings.Split(core, ".")
-	// be tolerant if tag is missing components (though module tags should not)
-	for len(parts) < 3 {
-		parts = append(parts, "0")
+	if to == "" {
+		to = "HEAD"
 	}
 
-	major, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return "", fmt.Errorf("invalid major in %q: %w", tag, err)
-	}
-	minor, err := strconv.Atoi(parts[1])
-	if err != nil {
-		return "", fmt.Errorf("invalid minor in %q: %w", tag, err)
-	}
-	patch, err := strconv.Atoi(parts[2])
+	tags, err := repo.Tags("")
 	if err != nil {
-		return "", fmt.Errorf("invalid patch in %q: %w", tag, err)
-	}
-
-	if hotfix {
-		patch++
-	} else {
-		minor++
-		patch = 0
+		return Release{}, fmt.Errorf("tags: %w", err)
 	}
 
-	next := fmt.Sprintf("v%d.%d.%d", major, minor, patch)
-	// Return canonical just in case
-	return semver.Canonical(next), nil
-}
+	version, date := unreleased, cfg.releaseDate()
 
-// NextTag returns the next semantic version tag based on the latest tag found
-// on the given branch. When hotfix is true, a PATCH increment is performed; otherwise
-// MINOR is incremented and PATCH reset to 0.
-func NextTag(tagBranch string, hotfix bool) (string, error) {
-	latestTag, err := LatestTag(tagBranch)
-	if err != nil {
-		return "", fmt.Errorf("latest tag: %w", err)
+	if i := slices.IndexFunc(tags, func(t Tag) bool { return t.Name == to }); i != -1 {
+		version = to
+		if cfg.Date.IsZero() {
+			date = tags[i].Date
+		}
+		if from == "" && i > 0 {
+			from = tags[i-1].Name
+		}
 	}
-	nextTag, err := NextVersion(latestTag, hotfix)
+
+	commits, err := repo.Log(from, to)
 	if err != nil {
-		return "", fmt.Errorf("next version: %w", err)
+		return Release{}, fmt.Errorf("commits between %s and %s: %w", from, to, err)
 	}
-	return nextTag, nil
+
+	return NewRelease(version, date, commits, cfg), nil
 }
 
-// GenerateChangelog is a high-level helper that orchestrates generating the
-// changelog text based on common inputs you would pass via CLI flags.
-//
-// It performs the following steps:
-//  1. Determine the latest tag reachable from the given branch.
-//  2. Compute the next version (minor bump by default, patch when hotfix).
-//  3. Collect commits since the latest tag and render the changelog.
-//
-// Parameters:
-//   - tagBranch: branch on which to search the latest tag (defaults to "main" if empty)
-//   - hotfix: whether to bump PATCH instead of MINOR
-//   - skipTypes: Conventional Commit types to omit (e.g., feat, fix, chore)
-//   - skipScopes: scopes to omit from the output
-//
-// Returns the rendered changelog, or an error.
-func GenerateChangelog(tagBranch string, hotfix bool, skipTypes []string, skipScopes []string) (string, error) {
+// ReleaseHistory returns the releases of all tags of repo being a semantic
+// version, newest first. Each release is dated using the commit date of its
+// tag. It can be used to rebuild a complete changelog.
+func ReleaseHistory(repo Repository, cfg ChangelogConfig) ([]Release, error) {
 
-	latestTag, err := LatestTag(tagBranch)
+	tags, err := repo.Tags("")
 	if err != nil {
-		return "", fmt.Errorf("latest tag: %w", err)
+		return nil, fmt.Errorf("tags: %w", err)
 	}
 
-	nextTag, err := NextVersion(latestTag, hotfix)
-	if err != nil {
-		return "", fmt.Errorf("next version: %w", err)
-	}
+	releases := make([]Release, 0, len(tags))
 
-	commits, err := CommitsSince(latestTag)
-	if err != nil {
-		return "", fmt.Errorf("commits since %s: %w", latestTag, err)
+	for i := len(tags) - 1; i >= 0; i-- {
+		from := ""
+		if i > 0 {
+			from = tags[i-1].Name
+		}
+
+		commits, err := repo.Log(from, tags[i].Name)
+		if err != nil {
+			return nil, fmt.Errorf("commits of %s: %w", tags[i].Name, err)
+		}
+
+		releases = append(releases, NewRelease(tags[i].Name, tags[i].Date, commits, cfg))
 	}
 
-	entry := RenderChangelog(nextTag, commits, skipTypes, skipScopes)
-	return entry, nil
+	return releases, nil
 }
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func commitsFromMessages(messages ...string) []Commit {
+
+	commits := make([]Commit, len(messages))
+	for i, msg := range messages {
+		commits[i] = ParseCommitMessage(msg)
+	}
+	return commits
+}
+
+func TestRenderChangelog(t *testing.T) {
+	today := time.Now().Format(time.DateOnly)
+
+	t.Run("breaking changes", func(t *testing.T) {
+		commits := commitsFromMessages(
+			"feat!: remove Foo",
+			"fix: correct typo",
+			"chore: tidy up\n\nBREAKING CHANGE: requires Go 1.24",
+			"docs(readme): document Bar",
+			"Merge branch 'main'",
+		)
+
+		exp := "## [1.2.0] - " + today + "\n\n" +
+			"### Breaking Changes\n\n" +
+			"- requires Go 1.24\n" +
+			"- remove Foo\n\n" +
+			"### Added\n\n" +
+			"- remove Foo\n\n" +
+			"### Changed\n\n" +
+			"- **readme**: document Bar\n\n" +
+			"### Fixed\n\n" +
+			"- correct typo\n\n"
+
+		xt.Eq(t, exp, RenderChangelog("v1.2.0", commits, DefaultChangelogConfig()))
+	})
+
+	t.Run("skip types", func(t *testing.T) {
+		commits := commitsFromMessages(
+			"feat!: remove Foo",
+			"fix: correct typo",
+		)
+
+		exp := "## [1.2.0] - " + today + "\n\n" +
+			"### Fixed\n\n" +
+			"- correct typo\n\n"
+
+		xt.Eq(t, exp, RenderChangelog("v1.2.0", commits, ChangelogConfig{Types: map[string]string{"fix": "Fixed"}, SkipTypes: []string{"feat"}}))
+	})
+}
+
+func TestGenerateChangelog(t *testing.T) {
+	repo := NewMemoryRepository()
+	repo.CommitMessage("feat: first")
+	xt.OK(t, repo.Tag("v1.0.0"))
+	repo.CommitMessage("fix: second")
+	repo.CommitMessage("feat(api): third")
+
+	cfg := DefaultChangelogConfig()
+	cfg.Date = time.Date(2025, 11, 19, 12, 0, 0, 0, time.UTC)
+
+	have, err := GenerateChangelog(repo, "main", VersionOptions{Bump: BumpAuto}, cfg)
+	xt.OK(t, err)
+
+	exp := "## [1.1.0] - 2025-11-19\n\n" +
+		"### Added\n\n" +
+		"- **api**: third\n\n" +
+		"### Fixed\n\n" +
+		"- second\n\n"
+	xt.Eq(t, exp, have)
+
+	t.Run("next tag", func(t *testing.T) {
+		tag, err := NextTag(repo, "main", VersionOptions{Bump: BumpAuto, Prerelease: "rc"})
+		xt.OK(t, err)
+		xt.Eq(t, "v1.1.0-rc.1", tag)
+	})
+}
+
+func TestReleaseBetween(t *testing.T) {
+	repo := NewMemoryRepository()
+	repo.CommitMessage("feat: first")
+	xt.OK(t, repo.Tag("v1.0.0"))
+	repo.CommitMessage("fix: second")
+	xt.OK(t, repo.Tag("v1.0.1"))
+	repo.CommitMessage("feat(api): third")
+	xt.OK(t, repo.Tag("v1.1.0"))
+	repo.CommitMessage("feat: fourth")
+
+	render := func(release Release) string {
+		var b strings.Builder
+		xt.OK(t, MarkdownRenderer{}.Render(&b, release))
+		return b.String()
+	}
+
+	t.Run("previous tag", func(t *testing.T) {
+		release, err := ReleaseBetween(repo, "", "v1.1.0", DefaultChangelogConfig())
+		xt.OK(t, err)
+
+		// dated using commit date of the tag
+		exp := "## [1.1.0] - 2020-01-01\n\n### Added\n\n- **api**: third\n\n"
+		xt.Eq(t, exp, render(release))
+		xt.Eq(t, time.Date(2020, 1, 1, 0, 2, 0, 0, time.UTC), release.Date)
+	})
+
+	t.Run("between tags", func(t *testing.T) {
+		release, err := ReleaseBetween(repo, "v1.0.0", "v1.1.0", DefaultChangelogConfig())
+		xt.OK(t, err)
+		xt.Eq(t, 2, len(release.Sections))
+	})
+
+	t.Run("first tag", func(t *testing.T) {
+		release, err := ReleaseBetween(repo, "", "v1.0.0", DefaultChangelogConfig())
+		xt.OK(t, err)
+		xt.Eq(t, "## [1.0.0] - 2020-01-01\n\n### Added\n\n- first\n\n", render(release))
+	})
+
+	t.Run("unreleased", func(t *testing.T) {
+		release, err := ReleaseBetween(repo, "v1.1.0", "HEAD", DefaultChangelogConfig())
+		xt.OK(t, err)
+		xt.Eq(t, "## [Unreleased]\n\n### Added\n\n- fourth\n\n", render(release))
+	})
+}
+
+func TestReleaseHistory(t *testing.T) {
+	repo := NewMemoryRepository()
+	repo.CommitMessage("feat: first")
+	xt.OK(t, repo.Tag("v0.9.0"))
+	repo.CommitMessage("fix: second")
+	xt.OK(t, repo.Tag("xgrpc/v0.1.0")) // ignored, tag of other module
+	repo.CommitMessage("feat(api): third")
+	xt.OK(t, repo.Tag("v0.10.0")) // sorted by version, not by name
+	repo.CommitMessage("feat: not released")
+
+	releases, err := ReleaseHistory(repo, DefaultChangelogConfig())
+	xt.OK(t, err)
+	xt.Eq(t, 2, len(releases))
+
+	xt.Eq(t, "v0.10.0", releases[0].Version)
+	xt.Eq(t, time.Date(2020, 1, 1, 0, 2, 0, 0, time.UTC), releases[0].Date)
+	xt.Eq(t, 2, len(releases[0].Sections))
+
+	xt.Eq(t, "v0.9.0", releases[1].Version)
+	xt.Eq(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), releases[1].Date)
+	xt.Eq(t, []ReleaseSection{{Name: "Added", Entries: []ReleaseEntry{{Message: "first", Hash: releases[1].Sections[0].Entries[0].Hash}}}},
+		releases[1].Sections)
+}
@@ -0,0 +1,427 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// reReleaseHeading matches the heading of a release section, which names
+// a version or Unreleased; other level 2 headings are part of the content.
+var reReleaseHeading = regexp.MustCompile(`^## \[?((?i:unreleased)|v?\d+\.\d+\.\d+(?:[-+][0-9A-Za-z.+-]*)?)]?(?:\s|$)`)
+var reEntryScope = regexp.MustCompile(`^- \*\*([^*]+)\*\*:\s*(.*)$`)
+var reEntryLink = regexp.MustCompile(`\[([^]]*)]\([^)]*\)`)
+var reEntryRefs = regexp.MustCompile(`(\s*\((?:#\w+|[0-9a-f]{7,40})(?:, (?:#\w+|[0-9a-f]{7,40}))*\))+$`)
+var reLinkDefinition = regexp.MustCompile(`^\[([^]]+)]:\s*(\S+)\s*$`)
+var reCompareURL = regexp.MustCompile(`^(.*/compare/)(\S+?)\.\.\.(\S+)$`)
+var reTagPrefix = regexp.MustCompile(`^(.*?)\d+\.\d+`)
+
+const unreleased = "Unreleased"
+
+const defaultChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.0.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// ChangelogFile is a changelog document following the Keep a Changelog
+// format, for example, the content of CHANGELOG.md.
+type ChangelogFile struct {
+	// Header is everything before the first release section.
+	Header string
+	// Releases are the release sections in order they appear, including
+	// the Unreleased section (usually first).
+	Releases []ChangelogRelease
+	// Links are the link reference definitions found at the end of the
+	// document, for example, "[1.2.0]: https://example.com/compare/v1.1.0...v1.2.0".
+	Links []ChangelogLink
+}
+
+// ChangelogRelease is a section of a ChangelogFile documenting a single release.
+type ChangelogRelease struct {
+	// Heading is the complete heading line, for example "## [1.2.0] - 2025-11-19".
+	Heading string
+	// Version is the version found in the heading, for example "1.2.0", or "Unreleased".
+	Version string
+	// Body is the content following the heading.
+	Body string
+}
+
+// ChangelogLink is a Markdown link reference definition.
+type ChangelogLink struct {
+	Label string
+	URL   string
+}
+
+// NewChangelogFile returns a ChangelogFile with the default Keep a Changelog header.
+func NewChangelogFile() *ChangelogFile {
+
+	return &ChangelogFile{Header: defaultChangelogHeader}
+}
+
+// ParseChangelogFile parses content as a Keep a Changelog document.
+func ParseChangelogFile(content string) *ChangelogFile {
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	cf := &ChangelogFile{}
+
+	// link reference definitions at the end of the document
+	end := len(lines)
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		m := reLinkDefinition.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		cf.Links = append([]ChangelogLink{{Label: m[1], URL: m[2]}}, cf.Links...)
+		end = i
+	}
+	lines = lines[:end]
+
+	var header []string
+	var current *ChangelogRelease
+	var body []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.Trim(strings.Join(body, "\n"), "\n")
+			cf.Releases = append(cf.Releases, *current)
+		}
+		body = nil
+	}
+
+	for _, line := range lines {
+		if m := reReleaseHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			current = &ChangelogRelease{
+				Heading: strings.TrimRight(line, " \t"),
+				Version: m[1],
+			}
+			continue
+		}
+
+		if current == nil {
+			header = append(header, line)
+		} else {
+			body = append(body, line)
+		}
+	}
+	flush()
+
+	cf.Header = strings.Trim(strings.Join(header, "\n"), "\n")
+
+	return cf
+}
+
+// Release returns the release section for version, which is matched
+// ignoring a leading 'v' and case, and whether it was found.
+func (cf *ChangelogFile) Release(version string) (*ChangelogRelease, bool) {
+
+	version = strings.TrimPrefix(version, "v")
+	for i := range cf.Releases {
+		if strings.EqualFold(strings.TrimPrefix(cf.Releases[i].Version, "v"), version) {
+			return &cf.Releases[i], true
+		}
+	}
+	return nil, false
+}
+
+// AddRelease inserts section, as returned by RenderChangelog, as new release
+// after the header and the Unreleased section. Entries documented in the
+// Unreleased section are moved into the new release, leaving Unreleased empty.
+//
+// When the document uses compare links (for example, as generated by
+// GitHub or GitLab), the link for the new release is added and the
+// Unreleased link is updated to compare against the new release.
+//
+// When the version of section is already documented, nothing is changed
+// and false is returned.
+func (cf *ChangelogFile) AddRelease(section string) (bool, error) {
+
+	section = strings.TrimSpace(section)
+	heading, body, _ := strings.Cut(section, "\n")
+
+	m := reReleaseHeading.FindStringSubmatch(heading)
+	if m == nil {
+		return false, fmt.Errorf("section does not start with release heading")
+	}
+
+	release := ChangelogRelease{
+		Heading: strings.TrimRight(heading, " \t"),
+		Version: m[1],
+		Body:    strings.Trim(body, "\n"),
+	}
+
+	if strings.EqualFold(release.Version, unreleased) {
+		return false, fmt.Errorf("cannot add %s as release", unreleased)
+	}
+
+	if _, ok := cf.Release(release.Version); ok {
+		return false, nil
+	}
+
+	insertAt := 0
+	if u, ok := cf.Release(unreleased); ok {
+		release.Body = mergeReleaseBodies(u.Body, release.Body)
+		u.Body = ""
+		insertAt = 1
+	}
+
+	cf.Releases = append(cf.Releases[:insertAt],
+		append([]ChangelogRelease{release}, cf.Releases[insertAt:]...)...)
+
+	cf.addCompareLink(release.Version)
+
+	return true, nil
+}
+
+// addCompareLink adds the compare link for version and updates the link for
+// Unreleased. The base URL, previous tag, and tag naming are derived
+// from the existing compare links.
+func (cf *ChangelogFile) addCompareLink(version string) {
+
+	var base, previous, prefix string
+	unreleasedLink := -1
+
+	for i, l := range cf.Links {
+		m := reCompareURL.FindStringSubmatch(l.URL)
+		if m == nil {
+			continue
+		}
+
+		if strings.EqualFold(l.Label, unreleased) {
+			base, previous = m[1], m[2]
+			if m := reTagPrefix.FindStringSubmatch(previous); m != nil {
+				prefix = m[1]
+			}
+			unreleasedLink = i
+			break
+		}
+
+		if base == "" {
+			// first (most recent) release link
+			base, previous = m[1], m[3]
+			if strings.HasSuffix(previous, l.Label) {
+				prefix = strings.TrimSuffix(previous, l.Label)
+			}
+		}
+	}
+
+	if base == "" {
+		return
+	}
+
+	tag := prefix + version
+	link := ChangelogLink{Label: version, URL: base + previous + "..." + tag}
+
+	if unreleasedLink == -1 {
+		cf.Links = append([]ChangelogLink{link}, cf.Links...)
+		return
+	}
+
+	cf.Links[unreleasedLink].URL = base + tag + "...HEAD"
+	at := unreleasedLink + 1
+	cf.Links = append(cf.Links[:at], append([]ChangelogLink{link}, cf.Links[at:]...)...)
+}
+
+// String returns cf as Markdown document.
+func (cf *ChangelogFile) String() string {
+
+	var b strings.Builder
+
+	if cf.Header != "" {
+		b.WriteString(strings.Trim(cf.Header, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	for _, r := range cf.Releases {
+		b.WriteString(r.Heading)
+		b.WriteString("\n\n")
+		if body := strings.Trim(r.Body, "\n"); body != "" {
+			b.WriteString(body)
+			b.WriteString("\n\n")
+		}
+	}
+
+	for _, l := range cf.Links {
+		b.WriteString(fmt.Sprintf("[%s]: %s\n", l.Label, l.URL))
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+type releaseSubsection struct {
+	heading string
+	lines   []string
+}
+
+// splitReleaseBody splits the body of a release in the text before the
+// first subsection (preamble) and its subsections ("### Added", ..).
+func splitReleaseBody(body string) ([]string, []*releaseSubsection) {
+
+	var preamble []string
+	var subs []*releaseSubsection
+
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "### ") {
+			subs = append(subs, &releaseSubsection{heading: strings.TrimSpace(line)})
+			continue
+		}
+		if len(subs) == 0 {
+			preamble = append(preamble, line)
+		} else {
+			s := subs[len(subs)-1]
+			s.lines = append(s.lines, line)
+		}
+	}
+
+	return preamble, subs
+}
+
+// mergeReleaseBodies merges the entries of the body from into the body to.
+// Entries of subsections both have in common are listed first, leaving out
+// those of from also listed by to (see entryKey).
+func mergeReleaseBodies(from, to string) string {
+
+	if strings.TrimSpace(from) == "" {
+		return to
+	}
+
+	fromPreamble, fromSubs := splitReleaseBody(from)
+	toPreamble, toSubs := splitReleaseBody(to)
+
+	var b strings.Builder
+
+	writeBlock := func(lines []string) {
+		if block := strings.Trim(strings.Join(lines, "\n"), "\n"); block != "" {
+			b.WriteString(block)
+			b.WriteString("\n\n")
+		}
+	}
+
+	writeBlock(fromPreamble)
+	writeBlock(toPreamble)
+
+	merged := map[string]bool{}
+	for _, ts := range toSubs {
+		b.WriteString(ts.heading + "\n\n")
+		var lines []string
+		for _, fs := range fromSubs {
+			if fs.heading == ts.heading {
+				fromLines := withoutEntries(fs.lines, entryKeys(ts.lines))
+				lines = append(lines, strings.Trim(strings.Join(fromLines, "\n"), "\n"))
+				merged[fs.heading] = true
+			}
+		}
+		writeBlock(append(lines, strings.Trim(strings.Join(ts.lines, "\n"), "\n")))
+	}
+
+	for _, fs := range fromSubs {
+		if !merged[fs.heading] {
+			b.WriteString(fs.heading + "\n\n")
+			writeBlock(fs.lines)
+		}
+	}
+
+	return strings.Trim(b.String(), "\n")
+}
+
+// entryKey returns the key identifying the entry of a list item line as
+// written by MarkdownRenderer, using the same normalized message as
+// NewRelease does for finding duplicates. Links, and the issue references
+// and commit hash appended to the message, are ignored. The scope is
+// found in the line, or, for nested items, in the scope of the group.
+// When line starts a group of entries, the returned key is empty.
+func entryKey(line, group string) (key string, scope string) {
+
+	message := strings.TrimSpace(line)[2:]
+	if !strings.HasPrefix(line, " ") {
+		group = ""
+		if m := reEntryScope.FindStringSubmatch(line); m != nil {
+			group, message = m[1], m[2]
+			if message == "" {
+				return "", group
+			}
+		}
+	}
+
+	message = reEntryLink.ReplaceAllString(message, "$1")
+	message = reEntryRefs.ReplaceAllString(message, "")
+
+	return group + "\x00" + normalizeMessage(message), group
+}
+
+// isEntry returns whether line is a list item.
+func isEntry(line string) bool {
+
+	return strings.HasPrefix(strings.TrimSpace(line), "- ")
+}
+
+// entryKeys returns the keys of the entries listed in lines (see entryKey).
+func entryKeys(lines []string) map[string]bool {
+
+	keys := map[string]bool{}
+	var group string
+	for _, line := range lines {
+		if isEntry(line) {
+			var key string
+			if key, group = entryKey(line, group); key != "" {
+				keys[key] = true
+			}
+		}
+	}
+
+	return keys
+}
+
+// withoutEntries returns lines without the entries (and their continuation
+// lines) having one of keys. Groups of which all entries are left out
+// are removed as well.
+func withoutEntries(lines []string, keys map[string]bool) []string {
+
+	var kept []string
+	var group string
+	pending := -1 // index in lines of group heading not yet kept
+	dropping := false
+
+	for i, line := range lines {
+		if !isEntry(line) {
+			if !dropping || strings.TrimSpace(line) == "" {
+				kept = append(kept, line)
+			}
+			continue
+		}
+
+		var key string
+		key, group = entryKey(line, group)
+		switch {
+		case key == "":
+			pending, dropping = i, false
+			continue
+		case !strings.HasPrefix(line, " "):
+			pending = -1
+		}
+
+		if dropping = keys[key]; dropping {
+			continue
+		}
+
+		if pending != -1 {
+			kept = append(kept, lines[pending])
+			pending = -1
+		}
+		kept = append(kept, line)
+	}
+
+	return kept
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+const testChangelogFile = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+Some words about the upcoming release.
+
+### Fixed
+
+- handle empty input
+
+### Security
+
+- escape user input
+
+## [1.1.0] - 2025-01-02
+
+### Added
+
+- something
+
+## [1.0.0] - 2024-12-01
+
+- Initial release.
+
+[Unreleased]: https://example.com/o/r/compare/v1.1.0...HEAD
+[1.1.0]: https://example.com/o/r/compare/v1.0.0...v1.1.0
+[1.0.0]: https://example.com/o/r/releases/tag/v1.0.0
+`
+
+func TestParseChangelogFile(t *testing.T) {
+	cf := ParseChangelogFile(testChangelogFile)
+
+	xt.Eq(t, "# Changelog\n\nAll notable changes to this project will be documented in this file.", cf.Header)
+	xt.Eq(t, 3, len(cf.Releases))
+	xt.Eq(t, "Unreleased", cf.Releases[0].Version)
+	xt.Eq(t, "1.1.0", cf.Releases[1].Version)
+	xt.Eq(t, "## [1.1.0] - 2025-01-02", cf.Releases[1].Heading)
+	xt.Eq(t, "### Added\n\n- something", cf.Releases[1].Body)
+	xt.Eq(t, 3, len(cf.Links))
+	xt.Eq(t, ChangelogLink{Label: "1.0.0", URL: "https://example.com/o/r/releases/tag/v1.0.0"}, cf.Links[2])
+
+	t.Run("round trip", func(t *testing.T) {
+		xt.Eq(t, testChangelogFile, cf.String())
+	})
+
+	t.Run("other level 2 headings", func(t *testing.T) {
+		cf := ParseChangelogFile("# Changelog\n\n## Notes\n\nWritten by hand.\n\n" +
+			"## [1.1.0-rc.1] - 2025-01-02\n\n## Migrating\n\n- nothing\n\n## v1.0.0\n")
+
+		xt.Eq(t, "# Changelog\n\n## Notes\n\nWritten by hand.", cf.Header)
+		xt.Eq(t, 2, len(cf.Releases))
+		xt.Eq(t, "1.1.0-rc.1", cf.Releases[0].Version)
+		xt.Eq(t, "## Migrating\n\n- nothing", cf.Releases[0].Body)
+		xt.Eq(t, "v1.0.0", cf.Releases[1].Version)
+	})
+}
+
+func TestChangelogFile_AddRelease(t *testing.T) {
+	section := "## [1.2.0] - 2025-02-03\n\n### Added\n\n- new feature\n\n### Fixed\n\n- crash on start\n\n"
+
+	t.Run("moves unreleased and updates links", func(t *testing.T) {
+		cf := ParseChangelogFile(testChangelogFile)
+
+		added, err := cf.AddRelease(section)
+		xt.OK(t, err)
+		xt.Assert(t, added)
+
+		exp := `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+## [1.2.0] - 2025-02-03
+
+Some words about the upcoming release.
+
+### Added
+
+- new feature
+
+### Fixed
+
+- handle empty input
+- crash on start
+
+### Security
+
+- escape user input
+
+## [1.1.0] - 2025-01-02
+
+### Added
+
+- something
+
+## [1.0.0] - 2024-12-01
+
+- Initial release.
+
+[Unreleased]: https://example.com/o/r/compare/v1.2.0...HEAD
+[1.2.0]: https://example.com/o/r/compare/v1.1.0...v1.2.0
+[1.1.0]: https://example.com/o/r/compare/v1.0.0...v1.1.0
+[1.0.0]: https://example.com/o/r/releases/tag/v1.0.0
+`
+		xt.Eq(t, exp, cf.String())
+
+		t.Run("twice does not duplicate", func(t *testing.T) {
+			added, err := cf.AddRelease(section)
+			xt.OK(t, err)
+			xt.Assert(t, !added)
+			xt.Eq(t, exp, cf.String())
+		})
+	})
+
+	t.Run("unreleased entries also generated", func(t *testing.T) {
+		cf := ParseChangelogFile("# Changelog\n\n## [Unreleased]\n\n### Fixed\n\n" +
+			"- Crash on  start.\n  Happened on Mondays.\n- **api**:\n    - handle empty input\n- **cmd**: other\n")
+
+		added, err := cf.AddRelease("## [1.2.0] - 2025-02-03\n\n### Fixed\n\n" +
+			"- crash on start ([0123456](https://example.com/commit/0123456))\n" +
+			"- **api**: handle empty input ([#12](https://example.com/issues/12))\n")
+		xt.OK(t, err)
+		xt.Assert(t, added)
+
+		exp := "# Changelog\n\n## [Unreleased]\n\n## [1.2.0] - 2025-02-03\n\n### Fixed\n\n" +
+			"- **cmd**: other\n" +
+			"- crash on start ([0123456](https://example.com/commit/0123456))\n" +
+			"- **api**: handle empty input ([#12](https://example.com/issues/12))\n"
+		xt.Eq(t, exp, cf.String())
+	})
+
+	t.Run("without unreleased", func(t *testing.T) {
+		cf := ParseChangelogFile("# Changelog\n\n## [1.1.0] - 2025-01-02\n\n- something\n\n" +
+			"[1.1.0]: https://example.com/o/r/compare/v1.0.0...v1.1.0\n")
+
+		added, err := cf.AddRelease(section)
+		xt.OK(t, err)
+		xt.Assert(t, added)
+
+		exp := "# Changelog\n\n" + section +
+			"## [1.1.0] - 2025-01-02\n\n- something\n\n" +
+			"[1.2.0]: https://example.com/o/r/compare/v1.1.0...v1.2.0\n" +
+			"[1.1.0]: https://example.com/o/r/compare/v1.0.0...v1.1.0\n"
+		xt.Eq(t, exp, cf.String())
+	})
+
+	t.Run("module tags", func(t *testing.T) {
+		cf := ParseChangelogFile("# Changelog\n\n## [Unreleased]\n\n## [1.1.0] - 2025-01-02\n\n- something\n\n" +
+			"[Unreleased]: https://example.com/o/r/compare/xgrpc/v1.1.0...HEAD\n" +
+			"[1.1.0]: https://example.com/o/r/compare/xgrpc/v1.0.0...xgrpc/v1.1.0\n")
+
+		added, err := cf.AddRelease(section)
+		xt.OK(t, err)
+		xt.Assert(t, added)
+
+		exp := "# Changelog\n\n## [Unreleased]\n\n" + section +
+			"## [1.1.0] - 2025-01-02\n\n- something\n\n" +
+			"[Unreleased]: https://example.com/o/r/compare/xgrpc/v1.2.0...HEAD\n" +
+			"[1.2.0]: https://example.com/o/r/compare/xgrpc/v1.1.0...xgrpc/v1.2.0\n" +
+			"[1.1.0]: https://example.com/o/r/compare/xgrpc/v1.0.0...xgrpc/v1.1.0\n"
+		xt.Eq(t, exp, cf.String())
+	})
+
+	t.Run("new file", func(t *testing.T) {
+		cf := NewChangelogFile()
+
+		added, err := cf.AddRelease(section)
+		xt.OK(t, err)
+		xt.Assert(t, added)
+		xt.Eq(t, defaultChangelogHeader+"\n"+section[:len(section)-1], cf.String())
+	})
+
+	t.Run("invalid section", func(t *testing.T) {
+		_, err := NewChangelogFile().AddRelease("### Added\n\n- foo")
+		xt.KO(t, err)
+		xt.Eq(t, "section does not start with release heading", err.Error())
+	})
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var reFooter = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][A-Za-z0-9-]*)(: | #)(.*)$`)
+
+// Commit holds the information of a single Git commit.
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string
+	Date    time.Time
+	Subject string
+	// Body is the commit message without subject and without footers.
+	Body    string
+	Footers []Footer
+	// RawMessage is the commit message as stored by Git. It is empty when
+	// the commit was not read from a repository; see Message.
+	RawMessage string
+}
+
+// Footer is a trailer found in the last paragraph of a commit message, for
+// example "BREAKING CHANGE: drop support for Go 1.22" or "Refs #123".
+type Footer struct {
+	Token string
+	Value string
+}
+
+// ShortHash returns the abbreviated (7 characters) commit hash.
+func (c Commit) ShortHash() string {
+
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Footer returns the value of the first footer with the given token
+// (case-insensitive) and whether it was found.
+func (c Commit) Footer(token string) (string, bool) {
+
+	for _, f := range c.Footers {
+		if strings.EqualFold(f.Token, token) {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// BreakingChange returns the description found in the "BREAKING CHANGE"
+// (or "BREAKING-CHANGE") footer and whether it was found.
+func (c Commit) BreakingChange() (string, bool) {
+
+	for _, f := range c.Footers {
+		if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// Message returns the full commit message of c: subject, body, and
+// footers separated by blank lines. The message is rebuilt from these
+// parts; use RawMessage, when set, for the message as committed.
+func (c Commit) Message() string {
+
+	var b strings.Builder
+	b.WriteString(c.Subject)
+
+	if c.Body != "" {
+		b.WriteString("\n\n" + c.Body)
+	}
+
+	for i, f := range c.Footers {
+		if i == 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n" + f.Token + ": " + f.Value)
+	}
+
+	return b.String()
+}
+
+// ParseCommitMessage parses a full commit message into subject, body, and
+// footers. The returned Commit has no hash, author, or date.
+//
+// Footers are read from the last paragraph of the message following
+// the Conventional Commits specification: each footer starts with a token
+// followed by ": " or " #". Lines not starting a new footer are continuations
+// of the previous footer.
+func ParseCommitMessage(msg string) Commit {
+
+	msg = strings.TrimSpace(strings.ReplaceAll(msg, "\r\n", "\n"))
+
+	subject, rest, _ := strings.Cut(msg, "\n")
+	c := Commit{
+		Subject: strings.TrimSpace(subject),
+	}
+
+	rest = strings.Trim(rest, "\n")
+	if rest == "" {
+		return c
+	}
+
+	paragraphs := strings.Split(rest, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if footers := parseFooters(last); footers != nil {
+		c.Footers = footers
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+
+	c.Body = strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+
+	return c
+}
+
+func parseFooters(paragraph string) []Footer {
+
+	lines := strings.Split(paragraph, "\n")
+	if !reFooter.MatchString(lines[0]) {
+		return nil
+	}
+
+	var footers []Footer
+	for _, line := range lines {
+		if m := reFooter.FindStringSubmatch(line); m != nil {
+			value := m[3]
+			if m[2] == " #" {
+				value = "#" + value
+			}
+			footers = append(footers, Footer{Token: m[1], Value: value})
+			continue
+		}
+		f := &footers[len(footers)-1]
+		f.Value += "\n" + line
+	}
+
+	for i := range footers {
+		footers[i].Value = strings.TrimSpace(footers[i].Value)
+	}
+
+	return footers
+}
+
+// conventionalCommit is a commit subject parsed following the
+// Conventional Commits specification.
+type conventionalCommit struct {
+	Type         string
+	Scope        string
+	Description  string
+	Breaking     bool
+	BreakingNote string
+}
+
+// parseConventional parses the subject and footers of c. It returns false when
+// the subject does not follow the Conventional Commits specification.
+func parseConventional(c Commit) (conventionalCommit, bool) {
+
+	matches := reConventionalCommit.FindStringSubmatch(c.Subject)
+	if matches == nil {
+		return conventionalCommit{}, false
+	}
+
+	cc := conventionalCommit{
+		Type:        strings.ToLower(matches[1]),
+		Scope:       strings.Trim(matches[2], "()"),
+		Breaking:    matches[3] == "!",
+		Description: strings.TrimSpace(matches[4]),
+	}
+
+	if note, ok := c.BreakingChange(); ok {
+		cc.Breaking = true
+		cc.BreakingNote = strings.Join(strings.Fields(note), " ")
+	}
+
+	if cc.Breaking && cc.BreakingNote == "" {
+		cc.BreakingNote = cc.Description
+	}
+
+	return cc, true
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestParseCommitMessage(t *testing.T) {
+	t.Run("subject only", func(t *testing.T) {
+		c := ParseCommitMessage("feat: add something\n")
+		xt.Eq(t, "feat: add something", c.Subject)
+		xt.Eq(t, "", c.Body)
+		xt.Eq(t, 0, len(c.Footers))
+	})
+
+	t.Run("body and footers", func(t *testing.T) {
+		msg := "feat(api)!: remove Foo\n\nFoo was deprecated a long time ago.\n\n" +
+			"Second paragraph.\n\nBREAKING CHANGE: Foo is gone,\n  use Bar instead.\nRefs #123\n"
+
+		c := ParseCommitMessage(msg)
+		xt.Eq(t, "feat(api)!: remove Foo", c.Subject)
+		xt.Eq(t, "Foo was deprecated a long time ago.\n\nSecond paragraph.", c.Body)
+		xt.Eq(t, []Footer{
+			{Token: "BREAKING CHANGE", Value: "Foo is gone,\n  use Bar instead."},
+			{Token: "Refs", Value: "#123"},
+		}, c.Footers)
+
+		note, ok := c.BreakingChange()
+		xt.Assert(t, ok)
+		xt.Eq(t, "Foo is gone,\n  use Bar instead.", note)
+
+		ref, ok := c.Footer("refs")
+		xt.Assert(t, ok)
+		xt.Eq(t, "#123", ref)
+
+		t.Run("message round-trips", func(t *testing.T) {
+			xt.Eq(t, c, ParseCommitMessage(c.Message()))
+		})
+	})
+
+	t.Run("last paragraph not footers", func(t *testing.T) {
+		c := ParseCommitMessage("fix: something\n\nThis is: not a footer\nsince it has spaces.")
+		xt.Eq(t, "This is: not a footer\nsince it has spaces.", c.Body)
+		xt.Eq(t, 0, len(c.Footers))
+	})
+}
+
+func TestParseConventional(t *testing.T) {
+	cases := map[string]struct {
+		msg  string
+		exp  conventionalCommit
+		isCC bool
+	}{
+		"not conventional": {
+			msg: "Update README",
+		},
+		"scoped": {
+			msg:  "fix(xsql): handle empty password",
+			exp:  conventionalCommit{Type: "fix", Scope: "xsql", Description: "handle empty password"},
+			isCC: true,
+		},
+		"breaking marker": {
+			msg: "feat!: drop Go 1.22",
+			exp: conventionalCommit{Type: "feat", Description: "drop Go 1.22",
+				Breaking: true, BreakingNote: "drop Go 1.22"},
+			isCC: true,
+		},
+		"scoped breaking marker": {
+			msg: "feat(api)!: rename Foo",
+			exp: conventionalCommit{Type: "feat", Scope: "api", Description: "rename Foo",
+				Breaking: true, BreakingNote: "rename Foo"},
+			isCC: true,
+		},
+		"breaking footer": {
+			msg: "refactor(git): use Commit\n\nBREAKING-CHANGE: CommitsSince returns\n  []Commit",
+			exp: conventionalCommit{Type: "refactor", Scope: "git", Description: "use Commit",
+				Breaking: true, BreakingNote: "CommitsSince returns []Commit"},
+			isCC: true,
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			cc, ok := parseConventional(ParseCommitMessage(cs.msg))
+			xt.Eq(t, cs.isCC, ok)
+			xt.Eq(t, cs.exp, cc)
+		})
+	}
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangelogConfigFiles are the names of the files, in order of preference,
+// from which the changelog configuration is loaded (see FindChangelogConfig).
+var ChangelogConfigFiles = []string{".changelog.yaml", ".changelog.yml", ".changelog.json"}
+
+// ChangelogConfig configures how commits are organized in changelog sections.
+type ChangelogConfig struct {
+	// Types maps Conventional Commit types to changelog sections, for example
+	// "feat" to "Added". Types mapped to an empty section are omitted.
+	Types map[string]string `json:"types,omitempty" yaml:"types,omitempty"`
+	// Sections is the order in which sections are rendered. Sections not
+	// listed are rendered last, sorted by name.
+	Sections []string `json:"sections,omitempty" yaml:"sections,omitempty"`
+	// SkipTypes are Conventional Commit types to omit.
+	SkipTypes []string `json:"skipTypes,omitempty" yaml:"skipTypes,omitempty"`
+	// SkipScopes are scopes to omit.
+	SkipScopes []string `json:"skipScopes,omitempty" yaml:"skipScopes,omitempty"`
+	// CommitURL is a text/template producing the URL of a commit, for example,
+	// "https://github.com/golistic/xgo/commit/{{.Hash}}". Available are .Hash
+	// and .ShortHash. When empty, entries are not linked to commits.
+	CommitURL string `json:"commitURL,omitempty" yaml:"commitURL,omitempty"`
+	// IssueURL is a text/template producing the URL of an issue, for example,
+	// "https://github.com/golistic/xgo/issues/{{.Issue}}". Available is .Issue,
+	// the issue number. When empty, issue references are not linked.
+	IssueURL string `json:"issueURL,omitempty" yaml:"issueURL,omitempty"`
+	// Contributor is a text/template producing the entry of a contributor,
+	// for example, "{{.Name}}". Available are .Name and .Email. When empty,
+	// contributors are not listed.
+	Contributor string `json:"contributor,omitempty" yaml:"contributor,omitempty"`
+	// Lint configures the rules used to lint commit messages (see LintMessage).
+	Lint LintConfig `json:"lint,omitempty" yaml:"lint,omitempty"`
+	// Date is the release date. When zero, today is used.
+	Date time.Time `json:"-" yaml:"-"`
+}
+
+// DefaultChangelogConfig returns the configuration used when none is provided.
+// Sections follow Keep a Changelog, preceded by breaking changes, and
+// with reverts of released changes listed in "Reverted".
+func DefaultChangelogConfig() ChangelogConfig {
+
+	return ChangelogConfig{
+		Types: map[string]string{
+			"feat":     "Added",
+			"fix":      "Fixed",
+			"hotfix":   "Fixed",
+			"docs":     "Changed",
+			"style":    "Changed",
+			"refactor": "Changed",
+			"perf":     "Changed",
+			"build":    "Changed",
+			"revert":   sectionReverted,
+		},
+		Sections: []string{
+			sectionBreaking, "Added", "Changed", "Deprecated", "Removed", "Fixed", sectionReverted, "Security",
+		},
+		Lint: LintConfig{
+			// types are derived from Types; see LintConfig
+			MaxSubjectLength: DefaultLintConfig().MaxSubjectLength,
+		},
+	}
+}
+
+// LintConfig returns the lint configuration of cfg. When cfg.Lint has no
+// types, the allowed types are those of DefaultLintConfig, extended with the
+// types configured in Types and SkipTypes, so that types added for the
+// changelog do not have to be listed again.
+func (cfg ChangelogConfig) LintConfig() LintConfig {
+
+	lint := cfg.Lint
+	if len(lint.Types) > 0 {
+		return lint
+	}
+
+	types := DefaultLintConfig().Types
+	for _, t := range slices.Concat(slices.Sorted(maps.Keys(cfg.Types)), cfg.SkipTypes) {
+		if t = strings.ToLower(t); !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	lint.Types = types
+
+	return lint
+}
+
+// LoadChangelogConfig reads the changelog configuration from the YAML or
+// JSON file stored at path; the format is based on the file extension.
+// The configuration read from file extends DefaultChangelogConfig: types
+// are added to (or replace) the default types, while sections, when provided,
+// replace the default order.
+func LoadChangelogConfig(path string) (ChangelogConfig, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ChangelogConfig{}, err
+	}
+
+	var fileCfg ChangelogConfig
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &fileCfg)
+	case ".json":
+		err = json.Unmarshal(data, &fileCfg)
+	default:
+		return ChangelogConfig{}, fmt.Errorf("unsupported changelog configuration format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return ChangelogConfig{}, fmt.Errorf("changelog configuration %s: %w", path, err)
+	}
+
+	cfg := DefaultChangelogConfig()
+	cfg.merge(fileCfg)
+
+	if err := cfg.Validate(); err != nil {
+		return ChangelogConfig{}, fmt.Errorf("changelog configuration %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// FindChangelogConfig returns the path to the first of ChangelogConfigFiles
+// found in dir, and whether one was found.
+func FindChangelogConfig(dir string) (string, bool) {
+
+	for _, name := range ChangelogConfigFiles {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); !errors.Is(err, fs.ErrNotExist) {
+			// when not readable, loading reports the problem
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// merge merges other into cfg. Types, like the types of commits, are
+// stored in lower case.
+func (cfg *ChangelogConfig) merge(other ChangelogConfig) {
+
+	if cfg.Types == nil {
+		cfg.Types = map[string]string{}
+	}
+	for t, section := range other.Types {
+		cfg.Types[strings.ToLower(t)] = section
+	}
+
+	if len(other.Sections) > 0 {
+		cfg.Sections = slices.Clone(other.Sections)
+	}
+
+	for _, t := range other.SkipTypes {
+		cfg.SkipTypes = append(cfg.SkipTypes, strings.ToLower(t))
+	}
+	cfg.SkipScopes = append(cfg.SkipScopes, other.SkipScopes...)
+	cfg.Lint.merge(other.Lint)
+
+	for _, p := range []struct {
+		dst *string
+		src string
+	}{
+		{&cfg.CommitURL, other.CommitURL},
+		{&cfg.IssueURL, other.IssueURL},
+		{&cfg.Contributor, other.Contributor},
+	} {
+		if p.src != "" {
+			*p.dst = p.src
+		}
+	}
+}
+
+// Validate checks whether the templates of cfg are valid.
+func (cfg ChangelogConfig) Validate() error {
+
+	for _, t := range []struct {
+		name string
+		text string
+		data any
+	}{
+		{"commitURL", cfg.CommitURL, commitTemplateData{Hash: "0123456789abcdef", ShortHash: "0123456"}},
+		{"issueURL", cfg.IssueURL, issueTemplateData{Issue: "1"}},
+		{"contributor", cfg.Contributor, contributorTemplateData{Name: "Alice", Email: "alice@example.com"}},
+	} {
+		if t.text == "" {
+			continue
+		}
+		if _, err := executeTemplate(t.text, t.data); err != nil {
+			return fmt.Errorf("invalid %s template: %w", t.name, err)
+		}
+	}
+
+	return nil
+}
+
+type commitTemplateData struct {
+	Hash      string
+	ShortHash string
+}
+
+type issueTemplateData struct {
+	Issue string
+}
+
+type contributorTemplateData struct {
+	Name  string
+	Email string
+}
+
+// executeTemplate parses text as text/template and executes it using data.
+func executeTemplate(text string, data any) (string, error) {
+
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// section returns the section of the Conventional Commit type, and whether
+// the type is included in the changelog.
+func (cfg ChangelogConfig) section(commitType string) (string, bool) {
+
+	s, ok := cfg.Types[strings.ToLower(commitType)]
+	if !ok {
+		for t, section := range cfg.Types {
+			if strings.EqualFold(t, commitType) {
+				s, ok = section, true
+				break
+			}
+		}
+	}
+	return s, ok && s != ""
+}
+
+// skipType returns whether commits of the Conventional Commit type
+// are omitted.
+func (cfg ChangelogConfig) skipType(commitType string) bool {
+
+	return slices.ContainsFunc(cfg.SkipTypes, func(t string) bool {
+		return strings.EqualFold(t, commitType)
+	})
+}
+
+// releaseDate returns cfg.Date, or today when not set.
+func (cfg ChangelogConfig) releaseDate() time.Time {
+
+	if cfg.Date.IsZero() {
+		return time.Now()
+	}
+	return cfg.Date
+}
+
+// sectionOrder returns the names of the given sections in order they
+// must be rendered.
+func (cfg ChangelogConfig) sectionOrder(names []string) []string {
+
+	var order, rest []string
+
+	for _, s := range cfg.Sections {
+		if slices.Contains(names, s) {
+			order = append(order, s)
+		}
+	}
+
+	for _, s := range names {
+		if !slices.Contains(order, s) && !slices.Contains(rest, s) {
+			rest = append(rest, s)
+		}
+	}
+	slices.Sort(rest)
+
+	return append(order, rest...)
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestLoadChangelogConfig(t *testing.T) {
+	yamlConfig := `types:
+  security: Security
+  deps: Dependencies
+  docs: ""
+sections: [Security, Added, Fixed]
+skipScopes: [internal]
+`
+	jsonConfig := `{
+  "types": {"security": "Security", "deps": "Dependencies", "docs": ""},
+  "sections": ["Security", "Added", "Fixed"],
+  "skipScopes": ["internal"]
+}`
+
+	for name, content := range map[string]string{".changelog.yaml": yamlConfig, ".changelog.json": jsonConfig} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			xt.OK(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+
+			path, ok := FindChangelogConfig(dir)
+			xt.Assert(t, ok)
+			xt.Eq(t, filepath.Join(dir, name), path)
+
+			cfg, err := LoadChangelogConfig(path)
+			xt.OK(t, err)
+
+			xt.Eq(t, "Security", cfg.Types["security"])
+			xt.Eq(t, "Dependencies", cfg.Types["deps"])
+			xt.Eq(t, "Added", cfg.Types["feat"]) // from default
+			_, ok = cfg.section("docs")
+			xt.Assert(t, !ok, "docs expected to be omitted")
+			xt.Eq(t, []string{"Security", "Added", "Fixed"}, cfg.Sections)
+			xt.Eq(t, []string{"internal"}, cfg.SkipScopes)
+		})
+	}
+
+	t.Run("types in any case", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".changelog.yaml")
+		xt.OK(t, os.WriteFile(path, []byte("types:\n  Feat: Features\n  DOCS: Documentation\n"+
+			"skipTypes: [Chore]\n"), 0o644))
+
+		cfg, err := LoadChangelogConfig(path)
+		xt.OK(t, err)
+
+		xt.Eq(t, "Features", cfg.Types["feat"])
+		section, ok := cfg.section("docs")
+		xt.Assert(t, ok)
+		xt.Eq(t, "Documentation", section)
+
+		release := NewRelease("v1.0.0", time.Time{}, []Commit{
+			{Subject: "feat: add endpoint"},
+			{Subject: "chore: update tooling"},
+			{Subject: "Docs: explain usage"},
+		}, cfg)
+		xt.Eq(t, 2, len(release.Sections))
+		xt.Eq(t, "Documentation", release.Sections[0].Name)
+		xt.Eq(t, "Features", release.Sections[1].Name)
+	})
+
+	t.Run("lint", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".changelog.yaml")
+		xt.OK(t, os.WriteFile(path, []byte("lint:\n  scopes: [xsql, xmaps]\n  requireIssue: true\n"+
+			"  disable: [subject-imperative]\n"), 0o644))
+
+		cfg, err := LoadChangelogConfig(path)
+		xt.OK(t, err)
+		xt.Eq(t, DefaultLintConfig().Types, cfg.LintConfig().Types)
+		xt.Eq(t, 72, cfg.Lint.MaxSubjectLength)
+		xt.Eq(t, []string{"xsql", "xmaps"}, cfg.Lint.Scopes)
+		xt.Assert(t, cfg.Lint.RequireIssue)
+		xt.Eq(t, []string{LintImperative}, cfg.Lint.Disable)
+	})
+
+	t.Run("lint types derived from types", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".changelog.yaml")
+		xt.OK(t, os.WriteFile(path, []byte("types:\n  deps: Dependencies\nskipTypes: [wip]\n"), 0o644))
+
+		cfg, err := LoadChangelogConfig(path)
+		xt.OK(t, err)
+		xt.Eq(t, append(DefaultLintConfig().Types, "deps", "wip"), cfg.LintConfig().Types)
+		xt.Eq(t, 0, len(LintMessage("deps: upgrade yaml.v3", cfg.LintConfig())))
+	})
+
+	t.Run("lint types configured", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".changelog.yaml")
+		xt.OK(t, os.WriteFile(path, []byte("types:\n  deps: Dependencies\nlint:\n  types: [feat, fix]\n"), 0o644))
+
+		cfg, err := LoadChangelogConfig(path)
+		xt.OK(t, err)
+		xt.Eq(t, []string{"feat", "fix"}, cfg.LintConfig().Types)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, ok := FindChangelogConfig(t.TempDir())
+		xt.Assert(t, !ok)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "changelog.toml")
+		xt.OK(t, os.WriteFile(path, []byte(""), 0o644))
+		_, err := LoadChangelogConfig(path)
+		xt.KO(t, err)
+		xt.Eq(t, `unsupported changelog configuration format ".toml"`, err.Error())
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".changelog.json")
+		xt.OK(t, os.WriteFile(path, []byte("{"), 0o644))
+		_, err := LoadChangelogConfig(path)
+		xt.KO(t, err)
+	})
+}
+
+func TestRenderChangelog_config(t *testing.T) {
+	cfg := DefaultChangelogConfig()
+	cfg.merge(ChangelogConfig{
+		Types:    map[string]string{"security": "Security", "deps": "Dependencies", "chore": "Maintenance"},
+		Sections: []string{"Security", "Fixed"},
+	})
+
+	commits := commitsFromMessages(
+		"deps: upgrade x/mod",
+		"fix: correct typo",
+		"chore: tidy up",
+		"security: escape input",
+		"test: more tests",
+	)
+
+	exp := "## [1.2.0] - " + time.Now().Format(time.DateOnly) + "\n\n" +
+		"### Security\n\n- escape input\n\n" +
+		"### Fixed\n\n- correct typo\n\n" +
+		"### Dependencies\n\n- upgrade x/mod\n\n" +
+		"### Maintenance\n\n- tidy up\n\n"
+
+	xt.Eq(t, exp, RenderChangelog("v1.2.0", commits, cfg))
+}
+
+func TestChangelogConfig_Validate(t *testing.T) {
+	cfg := ChangelogConfig{
+		CommitURL:   "https://example.com/commit/{{.Hash}}",
+		IssueURL:    "https://example.com/issues/{{.Issue}}",
+		Contributor: "{{.Name}}",
+	}
+	xt.OK(t, cfg.Validate())
+
+	t.Run("invalid syntax", func(t *testing.T) {
+		cfg := ChangelogConfig{CommitURL: "https://example.com/commit/{{.Hash"}
+		xt.KO(t, cfg.Validate())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		cfg := ChangelogConfig{IssueURL: "https://example.com/issues/{{.Number}}"}
+		err := cfg.Validate()
+		xt.KO(t, err)
+		xt.MatchString(t, `^invalid issueURL template: .*Number`, err.Error())
+	})
+}
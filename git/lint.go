@@ -0,0 +1,288 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lint rules reported by LintMessage. Rules can be disabled using
+// LintConfig.Disable.
+const (
+	LintHeaderFormat     = "header-format"
+	LintTypeEnum         = "type-enum"
+	LintScopeEnum        = "scope-enum"
+	LintScopeRequired    = "scope-required"
+	LintSubjectMaxLength = "subject-max-length"
+	LintSubjectPeriod    = "subject-period"
+	LintImperative       = "subject-imperative"
+	LintBodyLeadingBlank = "body-leading-blank"
+	LintIssueReference   = "issue-reference"
+)
+
+// scissors is the line after which Git ignores the commit message,
+// for example when using "git commit --verbose".
+const scissors = "# ------------------------ >8 ------------------------"
+
+// LintConfig configures the rules checked by LintMessage.
+type LintConfig struct {
+	// Types are the allowed Conventional Commit types. When empty,
+	// any type is allowed.
+	Types []string `json:"types,omitempty" yaml:"types,omitempty"`
+	// Scopes are the allowed scopes. When empty, any scope is allowed.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	// RequireScope reports commits without scope.
+	RequireScope bool `json:"requireScope,omitempty" yaml:"requireScope,omitempty"`
+	// RequireIssue reports messages not referencing an issue, like "#123".
+	RequireIssue bool `json:"requireIssue,omitempty" yaml:"requireIssue,omitempty"`
+	// MaxSubjectLength is the maximum length, in characters, of the subject
+	// line. When zero, the length is not checked.
+	MaxSubjectLength int `json:"maxSubjectLength,omitempty" yaml:"maxSubjectLength,omitempty"`
+	// Disable lists the rules not to check, for example "subject-imperative".
+	Disable []string `json:"disable,omitempty" yaml:"disable,omitempty"`
+}
+
+// DefaultLintConfig returns the lint configuration used when none is provided.
+func DefaultLintConfig() LintConfig {
+
+	return LintConfig{
+		Types: []string{
+			"feat", "fix", "hotfix", "docs", "style", "refactor", "perf",
+			"build", "test", "ci", "chore", "revert",
+		},
+		MaxSubjectLength: 72,
+	}
+}
+
+// merge merges other into cfg.
+func (cfg *LintConfig) merge(other LintConfig) {
+
+	if len(other.Types) > 0 {
+		cfg.Types = slices.Clone(other.Types)
+	}
+	if len(other.Scopes) > 0 {
+		cfg.Scopes = slices.Clone(other.Scopes)
+	}
+	if other.MaxSubjectLength != 0 {
+		cfg.MaxSubjectLength = other.MaxSubjectLength
+	}
+	cfg.RequireScope = cfg.RequireScope || other.RequireScope
+	cfg.RequireIssue = cfg.RequireIssue || other.RequireIssue
+	cfg.Disable = append(cfg.Disable, other.Disable...)
+}
+
+func (cfg LintConfig) enabled(rule string) bool {
+
+	return !slices.Contains(cfg.Disable, rule)
+}
+
+// LintProblem is a violation of a lint rule found by LintMessage.
+type LintProblem struct {
+	// Line is the line number (starting at 1) within the message.
+	Line int
+	// Column is the column (starting at 1, counting characters) within Line.
+	Column  int
+	Rule    string
+	Message string
+}
+
+// String returns p as "line:column: message (rule)".
+func (p LintProblem) String() string {
+
+	return fmt.Sprintf("%d:%d: %s (%s)", p.Line, p.Column, p.Message, p.Rule)
+}
+
+// LintMessage checks the commit message msg against the rules configured
+// by cfg, and returns the problems found, ordered by line and column.
+//
+// Like Git does, lines starting with '#' are ignored, as is everything
+// following the scissors line written by "git commit --verbose". Merge
+// commits, commits created by "git revert", and commits to be squashed
+// using "git rebase --autosquash" (fixup!, squash!, amend!) are accepted
+// as is.
+func LintMessage(msg string, cfg LintConfig) []LintProblem {
+
+	lines := strings.Split(strings.ReplaceAll(msg, "\r\n", "\n"), "\n")
+
+	// line numbers of message lines, excluding comments
+	var numbers []int
+	var content []string
+	for i, line := range lines {
+		if line == scissors {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		numbers = append(numbers, i+1)
+		content = append(content, line)
+	}
+
+	// leading blank lines are removed by Git
+	for len(content) > 0 && strings.TrimSpace(content[0]) == "" {
+		numbers, content = numbers[1:], content[1:]
+	}
+
+	if len(content) == 0 {
+		return []LintProblem{{Line: 1, Column: 1, Rule: LintHeaderFormat, Message: "empty commit message"}}
+	}
+
+	var problems []LintProblem
+	report := func(line, column int, rule, format string, a ...any) {
+		if cfg.enabled(rule) {
+			problems = append(problems, LintProblem{
+				Line:    line,
+				Column:  column,
+				Rule:    rule,
+				Message: fmt.Sprintf(format, a...),
+			})
+		}
+	}
+
+	subject, subjectLine := content[0], numbers[0]
+
+	for _, prefix := range []string{"Merge ", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(subject, prefix) {
+			return nil
+		}
+	}
+
+	if n := utf8.RuneCountInString(subject); cfg.MaxSubjectLength > 0 && n > cfg.MaxSubjectLength {
+		report(subjectLine, cfg.MaxSubjectLength+1, LintSubjectMaxLength,
+			"subject is %d characters long; maximum is %d", n, cfg.MaxSubjectLength)
+	}
+
+	if len(content) > 1 && strings.TrimSpace(content[1]) != "" {
+		report(numbers[1], 1, LintBodyLeadingBlank, "subject must be followed by a blank line")
+	}
+
+	if reRevertSubject.MatchString(subject) {
+		// created by "git revert"
+		return problems
+	}
+
+	m := reConventionalCommit.FindStringSubmatchIndex(subject)
+	if m == nil {
+		report(subjectLine, 1, LintHeaderFormat,
+			`subject must follow Conventional Commits as "type(scope): description"`)
+		return problems
+	}
+
+	column := func(offset int) int {
+		return utf8.RuneCountInString(subject[:offset]) + 1
+	}
+
+	commitType := subject[m[2]:m[3]]
+	if len(cfg.Types) > 0 && !slices.Contains(cfg.Types, strings.ToLower(commitType)) {
+		report(subjectLine, column(m[2]), LintTypeEnum,
+			"type %q is not one of %s", commitType, strings.Join(cfg.Types, ", "))
+	}
+
+	switch {
+	case m[4] == -1:
+		if cfg.RequireScope {
+			report(subjectLine, column(m[3]), LintScopeRequired, "scope is required")
+		}
+	case len(cfg.Scopes) > 0:
+		scope := subject[m[4]+1 : m[5]-1]
+		if !slices.Contains(cfg.Scopes, scope) {
+			report(subjectLine, column(m[4]+1), LintScopeEnum,
+				"scope %q is not one of %s", scope, strings.Join(cfg.Scopes, ", "))
+		}
+	}
+
+	description := subject[m[8]:m[9]]
+	switch {
+	case strings.TrimSpace(description) == "":
+		report(subjectLine, column(m[8]), LintHeaderFormat, "description is empty")
+	case strings.HasSuffix(description, "."):
+		report(subjectLine, column(len(subject)-1), LintSubjectPeriod, "subject must not end with a period")
+	}
+
+	if word, ok := nonImperative(description); ok {
+		report(subjectLine, column(m[8]), LintImperative,
+			"description should use the imperative mood (%q instead of %q)", word, firstWord(description))
+	}
+
+	if cfg.RequireIssue && !referencesIssue(ParseCommitMessage(strings.Join(content, "\n"))) {
+		report(subjectLine, 1, LintIssueReference, "message must reference an issue, for example #123")
+	}
+
+	slices.SortStableFunc(problems, func(a, b LintProblem) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+
+	return problems
+}
+
+// referencesIssue returns whether the message of c references an issue.
+func referencesIssue(c Commit) bool {
+
+	if reIssueReference.MatchString(c.Subject) || reIssueReference.MatchString(c.Body) {
+		return true
+	}
+
+	return slices.ContainsFunc(c.Footers, func(f Footer) bool {
+		return reIssueReference.MatchString(f.Value)
+	})
+}
+
+// imperativeVerbs are verbs commonly starting a commit description. They
+// are used to detect descriptions not using the imperative mood.
+var imperativeVerbs = []string{
+	"add", "allow", "bump", "change", "clean", "create", "delete", "deprecate",
+	"disable", "document", "drop", "enable", "ensure", "expose", "extract",
+	"fix", "handle", "implement", "improve", "introduce", "make", "merge",
+	"move", "prevent", "refactor", "remove", "rename", "replace", "return",
+	"revert", "rewrite", "set", "show", "simplify", "split", "support",
+	"update", "upgrade", "use",
+}
+
+func firstWord(s string) string {
+
+	word, _, _ := strings.Cut(strings.TrimSpace(s), " ")
+	return word
+}
+
+// nonImperative returns the imperative form of the first word of
+// description, and whether that word is a known verb not in the imperative
+// mood. For example, "added", "adding", or "adds" return "add".
+func nonImperative(description string) (string, bool) {
+
+	word := strings.ToLower(firstWord(description))
+
+	stem := func(suffix string) []string {
+		s, ok := strings.CutSuffix(word, suffix)
+		if !ok || s == "" {
+			return nil
+		}
+		candidates := []string{s, s + "e"}
+		if n := len(s); n > 1 && s[n-1] == s[n-2] {
+			candidates = append(candidates, s[:n-1]) // dropped, setting
+		}
+		if s, ok := strings.CutSuffix(s, "i"); ok {
+			candidates = append(candidates, s+"y") // simplified
+		}
+		return candidates
+	}
+
+	if slices.Contains(imperativeVerbs, word) {
+		return "", false
+	}
+
+	for _, suffix := range []string{"ed", "ing", "es", "s", "d"} {
+		for _, candidate := range stem(suffix) {
+			if slices.Contains(imperativeVerbs, candidate) {
+				return candidate, true
+			}
+		}
+	}
+
+	return "", false
+}
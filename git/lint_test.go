@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestLintMessage(t *testing.T) {
+	cfg := DefaultLintConfig()
+
+	t.Run("valid", func(t *testing.T) {
+		for _, msg := range []string{
+			"feat(api): add Foo",
+			"fix: handle empty input\n\nSome explanation.\n\nCloses: #12",
+			"\n\nchore: update dependencies\n# Please enter the commit message\n",
+			"Merge branch 'main' into feature",
+			"fixup! feat: add Foo",
+			"Revert \"feat: add Foo\"\n\nThis reverts commit 0123456.",
+			"fix: embed the static files",
+			"docs: address review comments",
+		} {
+			xt.Eq(t, 0, len(LintMessage(msg, cfg)), msg)
+		}
+	})
+
+	cases := map[string]struct {
+		msg string
+		cfg func(cfg *LintConfig)
+		exp []string
+	}{
+		"empty": {
+			msg: "# only comments\n\n",
+			exp: []string{"1:1: empty commit message (header-format)"},
+		},
+		"not conventional": {
+			msg: "Update README",
+			exp: []string{`1:1: subject must follow Conventional Commits as "type(scope): description" (header-format)`},
+		},
+		"unknown type": {
+			msg: "# comment\nfeature: add Foo",
+			exp: []string{`2:1: type "feature" is not one of ` + strings.Join(cfg.Types, ", ") + " (type-enum)"},
+		},
+		"scope not allowed": {
+			msg: "fix(xgrpc): handle deadline",
+			cfg: func(cfg *LintConfig) { cfg.Scopes = []string{"xsql", "xmaps"} },
+			exp: []string{`1:5: scope "xgrpc" is not one of xsql, xmaps (scope-enum)`},
+		},
+		"scope required": {
+			msg: "fix!: handle deadline",
+			cfg: func(cfg *LintConfig) { cfg.RequireScope = true },
+			exp: []string{"1:4: scope is required (scope-required)"},
+		},
+		"subject too long and period": {
+			msg: "fix: " + strings.Repeat("x", 20) + ".",
+			cfg: func(cfg *LintConfig) { cfg.MaxSubjectLength = 20 },
+			exp: []string{
+				"1:21: subject is 26 characters long; maximum is 20 (subject-max-length)",
+				"1:26: subject must not end with a period (subject-period)",
+			},
+		},
+		"not imperative": {
+			msg: "feat: Simplifies parsing",
+			exp: []string{`1:7: description should use the imperative mood ("simplify" instead of "Simplifies") (subject-imperative)`},
+		},
+		"not imperative disabled": {
+			msg: "feat: adding Foo",
+			cfg: func(cfg *LintConfig) { cfg.Disable = []string{LintImperative} },
+		},
+		"body without blank line": {
+			msg: "fix: handle deadline\nBody.",
+			exp: []string{"2:1: subject must be followed by a blank line (body-leading-blank)"},
+		},
+		"issue required": {
+			msg: "fix: handle deadline\n\nSee PR/#12 and foo#bar.",
+			cfg: func(cfg *LintConfig) { cfg.RequireIssue = true },
+			exp: []string{"1:1: message must reference an issue, for example #123 (issue-reference)"},
+		},
+		"issue in footer": {
+			msg: "fix: handle deadline\n\nRefs #12",
+			cfg: func(cfg *LintConfig) { cfg.RequireIssue = true },
+		},
+		"ignored after scissors": {
+			msg: "fix: handle deadline\n\n" + scissors + "\ndiff --git a/foo b/foo",
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultLintConfig()
+			if cs.cfg != nil {
+				cs.cfg(&cfg)
+			}
+
+			var have []string
+			for _, p := range LintMessage(cs.msg, cfg) {
+				have = append(have, p.String())
+			}
+			xt.Eq(t, cs.exp, have)
+		})
+	}
+}
+
+func TestNonImperative(t *testing.T) {
+	cases := map[string]string{
+		"added":      "add",
+		"Adds":       "add",
+		"adding":     "add",
+		"fixes":      "fix",
+		"updated":    "update",
+		"updating":   "update",
+		"dropped":    "drop",
+		"setting":    "set",
+		"simplified": "simplify",
+		"uses":       "use",
+	}
+
+	for word, exp := range cases {
+		t.Run(word, func(t *testing.T) {
+			have, ok := nonImperative(word + " something")
+			xt.Assert(t, ok)
+			xt.Eq(t, exp, have)
+		})
+	}
+
+	for _, word := range []string{"add", "embed", "bring", "address", "process", "Foo"} {
+		_, ok := nonImperative(word + " something")
+		xt.Assert(t, !ok, word)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MemoryRepository is a Repository keeping a linear history in memory. It is
+// meant to be used in tests, for example, to verify changelog generation
+// without needing an actual Git repository.
+//
+// Revisions are tags, (abbreviated) commit hashes, and "HEAD" or "main" (the
+// only branch), both resolving to the latest commit. Like ExecRepository,
+// other revisions result in an error.
+type MemoryRepository struct {
+	commits []Commit   // oldest first
+	files   [][]string // files changed by each commit
+	tags    map[string]int
+	order   []string // tags in order they were created
+}
+
+var _ Repository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository returns a new MemoryRepository without any commits.
+func NewMemoryRepository() *MemoryRepository {
+
+	return &MemoryRepository{
+		tags: map[string]int{},
+	}
+}
+
+// Commit adds c, changing files, to the history. When c has no hash, one
+// is generated from its message and position in history. When c has no date,
+// it is set one minute after the previous commit (starting at 2020-01-01 UTC).
+// The (updated) commit is returned.
+func (r *MemoryRepository) Commit(c Commit, files ...string) Commit {
+
+	if c.Date.IsZero() {
+		c.Date = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		if n := len(r.commits); n > 0 {
+			c.Date = r.commits[n-1].Date.Add(time.Minute)
+		}
+	}
+
+	if c.Hash == "" {
+		sum := sha1.Sum([]byte(fmt.Sprintf("%d\x00%s\x00%s", len(r.commits), c.Subject, c.Body)))
+		c.Hash = hex.EncodeToString(sum[:])
+	}
+
+	r.commits = append(r.commits, c)
+	r.files = append(r.files, files)
+
+	return c
+}
+
+// CommitMessage parses msg (see ParseCommitMessage) and adds it as
+// commit changing files to the history.
+func (r *MemoryRepository) CommitMessage(msg string, files ...string) Commit {
+
+	return r.Commit(ParseCommitMessage(msg), files...)
+}
+
+// Tag tags the latest commit using name.
+func (r *MemoryRepository) Tag(name string) error {
+
+	return r.CreateTag(name, "", "")
+}
+
+// CreateTag tags the commit at revision rev using name, like
+// ExecRepository.CreateTag does. When rev is empty, the latest commit is
+// tagged. The message is not kept.
+func (r *MemoryRepository) CreateTag(name, rev, _ string) error {
+
+	if len(r.commits) == 0 {
+		return fmt.Errorf("cannot tag %q; no commits", name)
+	}
+
+	if _, ok := r.tags[name]; ok {
+		return fmt.Errorf("tag %q already exists", name)
+	}
+
+	index := len(r.commits) - 1
+	if rev != "" {
+		var err error
+		if index, err = r.resolve(rev); err != nil {
+			return err
+		}
+	}
+
+	r.tags[name] = index
+	r.order = append(r.order, name)
+
+	return nil
+}
+
+// resolve returns the index in history of revision rev.
+func (r *MemoryRepository) resolve(rev string) (int, error) {
+
+	if i, ok := r.tags[rev]; ok {
+		return i, nil
+	}
+
+	if len(rev) >= 4 {
+		for i, c := range r.commits {
+			if strings.HasPrefix(c.Hash, rev) {
+				return i, nil
+			}
+		}
+	}
+
+	if (rev == "HEAD" || rev == "main") && len(r.commits) > 0 {
+		return len(r.commits) - 1, nil
+	}
+
+	return 0, fmt.Errorf("unknown revision %q", rev)
+}
+
+// LatestTag returns the most recent tag starting with prefix. The branch is
+// ignored since MemoryRepository has only one branch. See Repository for
+// how prefix is used.
+func (r *MemoryRepository) LatestTag(_, prefix string) (string, error) {
+
+	latest, index := "", -1
+	for _, name := range r.order {
+		if !strings.HasPrefix(name, prefix) || strings.Contains(name[len(prefix):], "/") {
+			continue
+		}
+		if r.tags[name] >= index {
+			latest, index = name, r.tags[name]
+		}
+	}
+
+	if latest == "" {
+		return "", fmt.Errorf("no tags found")
+	}
+
+	return latest, nil
+}
+
+// Tags returns the tags being a semantic version, oldest first. See
+// Repository for how prefix is used.
+func (r *MemoryRepository) Tags(prefix string) ([]Tag, error) {
+
+	tags := make([]Tag, 0, len(r.order))
+	for _, name := range r.order {
+		c := r.commits[r.tags[name]]
+		tags = append(tags, Tag{Name: name, Hash: c.Hash, Date: c.Date})
+	}
+
+	return sortTags(tags, prefix), nil
+}
+
+// Log returns the commits after from up to and including to, newest first.
+// When paths are provided, only commits changing files within these paths
+// are returned.
+func (r *MemoryRepository) Log(from, to string, paths ...string) ([]Commit, error) {
+
+	end, err := r.resolve(to)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if from != "" {
+		i, err := r.resolve(from)
+		if err != nil {
+			return nil, err
+		}
+		start = i + 1
+	}
+
+	commits := []Commit{}
+	for i := end; i >= start; i-- {
+		if len(paths) == 0 || slices.ContainsFunc(r.files[i], func(f string) bool {
+			return withinPaths(f, paths)
+		}) {
+			commits = append(commits, r.commits[i])
+		}
+	}
+
+	return commits, nil
+}
+
+// withinPaths returns whether file is one of paths, or is stored
+// within one of the paths (directories).
+func withinPaths(file string, paths []string) bool {
+
+	file = path.Clean(file)
+	for _, p := range paths {
+		p = path.Clean(p)
+		if p == "." || file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	_, err := repo.LatestTag("main", "")
+	xt.KO(t, err)
+	xt.KO(t, repo.Tag("v0.1.0"))
+
+	first := repo.CommitMessage("feat: first")
+	xt.OK(t, repo.Tag("v1.0.0"))
+	second := repo.CommitMessage("fix: second")
+	repo.CommitMessage("feat: third")
+
+	xt.Eq(t, 40, len(first.Hash))
+	xt.Assert(t, first.Hash != second.Hash)
+	xt.Assert(t, second.Date.After(first.Date))
+
+	xt.KO(t, repo.Tag("v1.0.0"))
+
+	tag, err := repo.LatestTag("main", "")
+	xt.OK(t, err)
+	xt.Eq(t, "v1.0.0", tag)
+
+	t.Run("since tag", func(t *testing.T) {
+		commits, err := repo.Log("v1.0.0", "HEAD")
+		xt.OK(t, err)
+		xt.Eq(t, 2, len(commits))
+		xt.Eq(t, "feat: third", commits[0].Subject)
+		xt.Eq(t, "fix: second", commits[1].Subject)
+	})
+
+	t.Run("between hashes", func(t *testing.T) {
+		commits, err := repo.Log(first.ShortHash(), second.Hash)
+		xt.OK(t, err)
+		xt.Eq(t, 1, len(commits))
+		xt.Eq(t, "fix: second", commits[0].Subject)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		commits, err := repo.Log("", "main")
+		xt.OK(t, err)
+		xt.Eq(t, 3, len(commits))
+	})
+
+	t.Run("create tag at revision", func(t *testing.T) {
+		xt.OK(t, repo.CreateTag("v1.1.0", second.Hash, "v1.1.0"))
+		xt.KO(t, repo.CreateTag("v1.1.0", "", "again"))
+
+		commits, err := repo.Log("v1.1.0", "HEAD")
+		xt.OK(t, err)
+		xt.Eq(t, 1, len(commits))
+		xt.Eq(t, "feat: third", commits[0].Subject)
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		_, err := repo.Log("v9.9.9", "HEAD")
+		xt.KO(t, err)
+		xt.Eq(t, `unknown revision "v9.9.9"`, err.Error())
+
+		_, err = repo.Log("", "develop")
+		xt.KO(t, err)
+		xt.KO(t, repo.CreateTag("v2.0.0", "HEAD~1", ""))
+	})
+
+	t.Run("nothing since latest", func(t *testing.T) {
+		commits, err := repo.Log("HEAD", "HEAD")
+		xt.OK(t, err)
+		xt.Eq(t, 0, len(commits))
+	})
+}
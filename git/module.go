@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ModuleRepository is a Repository limited to a module stored in a
+// subdirectory of a repository holding multiple modules (monorepo).
+//
+// Following the Go module conventions, tags of a module are prefixed with
+// the module directory, for example, "xgrpc/v1.2.0" for the module found
+// in the directory "xgrpc". Only commits changing files within the module
+// directory are returned by Log.
+type ModuleRepository struct {
+	Repository
+	// Dir is the directory of the module relative to the root of the repository.
+	Dir string
+}
+
+var _ Repository = (*ModuleRepository)(nil)
+
+// NewModuleRepository returns a Repository limited to the module
+// stored in dir, relative to the root of repo. When dir is empty or
+// ".", the root module is used.
+func NewModuleRepository(repo Repository, dir string) *ModuleRepository {
+
+	dir = path.Clean(filepath.ToSlash(dir))
+	if dir == "." {
+		dir = ""
+	}
+
+	return &ModuleRepository{
+		Repository: repo,
+		Dir:        dir,
+	}
+}
+
+// TagPrefix returns the prefix of the tags of the module, for
+// example, "xgrpc/". The root module has no prefix.
+func (r *ModuleRepository) TagPrefix() string {
+
+	if r.Dir == "" {
+		return ""
+	}
+	return r.Dir + "/"
+}
+
+// LatestTag returns the most recent tag of the module reachable from branch.
+// The prefix is appended to the tag prefix of the module.
+func (r *ModuleRepository) LatestTag(branch, prefix string) (string, error) {
+
+	return r.Repository.LatestTag(branch, r.TagPrefix()+prefix)
+}
+
+// Tags returns the tags of the module, oldest first. The prefix is
+// appended to the tag prefix of the module.
+func (r *ModuleRepository) Tags(prefix string) ([]Tag, error) {
+
+	return r.Repository.Tags(r.TagPrefix() + prefix)
+}
+
+// Log returns the commits changing files within the module. When paths
+// are provided, they are relative to the module directory.
+func (r *ModuleRepository) Log(from, to string, paths ...string) ([]Commit, error) {
+
+	if r.Dir == "" {
+		return r.Repository.Log(from, to, paths...)
+	}
+
+	if len(paths) == 0 {
+		return r.Repository.Log(from, to, r.Dir)
+	}
+
+	modulePaths := make([]string, len(paths))
+	for i, p := range paths {
+		modulePaths[i] = path.Join(r.Dir, p)
+	}
+
+	return r.Repository.Log(from, to, modulePaths...)
+}
+
+// splitTag splits tag into the module prefix and version, for
+// example, "xgrpc/v1.2.0" into "xgrpc/" and "v1.2.0".
+func splitTag(tag string) (string, string) {
+
+	if i := strings.LastIndexByte(tag, '/'); i != -1 {
+		return tag[:i+1], tag[i+1:]
+	}
+	return "", tag
+}
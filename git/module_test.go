@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestModuleRepository(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	repo.CommitMessage("feat: root module", "go.mod")
+	xt.OK(t, repo.Tag("v1.0.0"))
+	repo.CommitMessage("feat(xgrpc): add module", "xgrpc/go.mod", "xgrpc/server.go")
+	xt.OK(t, repo.Tag("xgrpc/v0.1.0"))
+	repo.CommitMessage("fix(xgrpc): handle deadline", "xgrpc/server.go")
+	repo.CommitMessage("feat: add xconv.ParseBool", "xconv/bool.go")
+	repo.CommitMessage("feat(xgrpc): add interceptor", "xgrpc/interceptor.go", "go.work")
+	xt.OK(t, repo.Tag("xgrpc/v0.2.0"))
+	repo.CommitMessage("fix(xgrpc): close listener", "xgrpc/server.go")
+	repo.CommitMessage("docs: update README", "README.md", "xgrpcx/README.md")
+
+	t.Run("root module", func(t *testing.T) {
+		for _, dir := range []string{"", ".", "./"} {
+			module := NewModuleRepository(repo, dir)
+			xt.Eq(t, "", module.TagPrefix())
+
+			tag, err := module.LatestTag("main", "")
+			xt.OK(t, err)
+			xt.Eq(t, "v1.0.0", tag)
+
+			commits, err := module.Log(tag, "HEAD")
+			xt.OK(t, err)
+			xt.Eq(t, 6, len(commits))
+		}
+	})
+
+	t.Run("module in subdirectory", func(t *testing.T) {
+		for _, dir := range []string{"xgrpc", "./xgrpc", "xgrpc/"} {
+			module := NewModuleRepository(repo, dir)
+			xt.Eq(t, "xgrpc/", module.TagPrefix())
+
+			tag, err := module.LatestTag("main", "")
+			xt.OK(t, err)
+			xt.Eq(t, "xgrpc/v0.2.0", tag)
+
+			commits, err := module.Log(tag, "HEAD")
+			xt.OK(t, err)
+			xt.Eq(t, 1, len(commits))
+			xt.Eq(t, "fix(xgrpc): close listener", commits[0].Subject)
+
+			commits, err = module.Log("xgrpc/v0.1.0", tag)
+			xt.OK(t, err)
+			xt.Eq(t, 2, len(commits))
+			xt.Eq(t, "feat(xgrpc): add interceptor", commits[0].Subject)
+			xt.Eq(t, "fix(xgrpc): handle deadline", commits[1].Subject)
+		}
+	})
+
+	t.Run("paths relative to module", func(t *testing.T) {
+		module := NewModuleRepository(repo, "xgrpc")
+
+		commits, err := module.Log("", "HEAD", "interceptor.go")
+		xt.OK(t, err)
+		xt.Eq(t, 1, len(commits))
+		xt.Eq(t, "feat(xgrpc): add interceptor", commits[0].Subject)
+	})
+
+	t.Run("module without tags", func(t *testing.T) {
+		_, err := NewModuleRepository(repo, "xconv").LatestTag("main", "")
+		xt.KO(t, err)
+	})
+
+	t.Run("changelog and next tag", func(t *testing.T) {
+		module := NewModuleRepository(repo, "xgrpc")
+		cfg := DefaultChangelogConfig()
+		cfg.Date = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		release, err := GenerateRelease(module, "main", VersionOptions{Bump: BumpAuto}, cfg)
+		xt.OK(t, err)
+		xt.Eq(t, "xgrpc/v0.2.1", release.Version)
+
+		exp := "## [0.2.1] - 2026-03-01\n\n" +
+			"### Fixed\n\n" +
+			"- **xgrpc**: close listener\n\n"
+		xt.Eq(t, exp, render(t, MarkdownRenderer{}, release))
+
+		tag, err := NextTag(module, "main", VersionOptions{Bump: BumpMinor, Prerelease: "rc"})
+		xt.OK(t, err)
+		xt.Eq(t, "xgrpc/v0.3.0-rc.1", tag)
+	})
+}
+
+func TestExecRepository_module(t *testing.T) {
+	repo := newTestExecRepository(t, []string{"feat: root"}, map[int]string{0: "v1.0.0"})
+
+	commit := func(file, msg string) {
+		xt.OK(t, os.MkdirAll(filepath.Join(repo.Dir, filepath.Dir(file)), 0o755))
+		xt.OK(t, os.WriteFile(filepath.Join(repo.Dir, file), []byte(msg), 0o644))
+		_, err := repo.run("add", file)
+		xt.OK(t, err)
+		_, err = repo.run("commit", "-q", "-m", msg)
+		xt.OK(t, err)
+	}
+
+	commit("xgrpc/go.mod", "feat(xgrpc): add module")
+	_, err := repo.run("tag", "xgrpc/v0.1.0")
+	xt.OK(t, err)
+	commit("xgrpc/server.go", "fix(xgrpc): handle deadline")
+	commit("xconv/bool.go", "feat: add ParseBool")
+	_, err = repo.run("tag", "xconv/v0.1.0")
+	xt.OK(t, err)
+
+	module := NewModuleRepository(repo, "xgrpc")
+
+	tag, err := module.LatestTag("HEAD", "")
+	xt.OK(t, err)
+	xt.Eq(t, "xgrpc/v0.1.0", tag)
+
+	commits, err := module.Log(tag, "HEAD")
+	xt.OK(t, err)
+	xt.Eq(t, 1, len(commits))
+	xt.Eq(t, "fix(xgrpc): handle deadline", commits[0].Subject)
+
+	t.Run("root module ignores module tags", func(t *testing.T) {
+		tag, err := NewModuleRepository(repo, ".").LatestTag("HEAD", "")
+		xt.OK(t, err)
+		xt.Eq(t, "v1.0.0", tag)
+	})
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"cmp"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+var reIssueReference = regexp.MustCompile(`(?:^|[^\w&/])#(\d+)\b`)
+
+// Release holds the changelog of a single release, organized in sections.
+type Release struct {
+	// Version is the tag of the release, for example "v1.2.0".
+	Version  string           `json:"version"`
+	Date     time.Time        `json:"date"`
+	Sections []ReleaseSection `json:"sections"`
+	// Contributors are the authors of the commits, rendered using the
+	// Contributor template of ChangelogConfig.
+	Contributors []string `json:"contributors,omitempty"`
+}
+
+// ReleaseSection is a section of a Release, for example "Added".
+type ReleaseSection struct {
+	Name    string         `json:"name"`
+	Entries []ReleaseEntry `json:"entries"`
+}
+
+// ReleaseEntry is a single change documented in a ReleaseSection.
+type ReleaseEntry struct {
+	Scope   string `json:"scope,omitempty"`
+	Message string `json:"message"`
+	// Hash is the hash of the commit documenting the entry.
+	Hash string `json:"hash,omitempty"`
+	// CommitURL is the URL of the commit, rendered using the CommitURL
+	// template of ChangelogConfig.
+	CommitURL string `json:"commitURL,omitempty"`
+	// Issues are the issues referenced in the message or footers
+	// (for example, "Closes: #45") of the commit.
+	Issues []ReleaseIssue `json:"issues,omitempty"`
+}
+
+// ShortHash returns the abbreviated (7 characters) commit hash of e.
+func (e ReleaseEntry) ShortHash() string {
+
+	return Commit{Hash: e.Hash}.ShortHash()
+}
+
+// ReleaseIssue is a reference to an issue, for example #123.
+type ReleaseIssue struct {
+	// ID is the issue number without leading '#'.
+	ID string `json:"id"`
+	// URL is the URL of the issue, rendered using the IssueURL template
+	// of ChangelogConfig.
+	URL string `json:"url,omitempty"`
+}
+
+// IsEmpty returns whether r has no entries.
+func (r Release) IsEmpty() bool {
+
+	return len(r.Sections) == 0
+}
+
+// NewRelease organizes commits, as returned by Repository.Log (newest
+// first), into the sections configured by cfg. Commits not following
+// the Conventional Commits specification are ignored.
+//
+// The result is deterministic: sections are ordered following cfg.Sections,
+// and within each section, entries without scope come first, followed by
+// entries sorted by scope. Entries with the same scope are listed
+// in the order they were committed.
+//
+// Breaking changes, marked with "!" after type or scope, or using the
+// "BREAKING CHANGE" footer, are additionally listed in the section
+// "Breaking Changes", even when the type is not mapped to a section.
+//
+// Squash-merge commits listing several Conventional Commits subjects in
+// their body are expanded into separate entries. Reverted commits are
+// omitted together with the revert when both are part of commits; reverts
+// of commits released before are listed in the section of the type
+// "revert" ("Reverted" by default).
+func NewRelease(tag string, date time.Time, commits []Commit, cfg ChangelogConfig) Release {
+
+	sections := map[string][]ReleaseEntry{}
+	contributors := map[string]Commit{}
+
+	// entries are duplicates when section, type, scope, and the normalized
+	// message are equal
+	type entryKey struct{ section, typ, scope, message string }
+	seen := map[entryKey]bool{}
+
+	addEntry := func(section, typ, scope, message string, commit Commit) {
+		if slices.Contains(cfg.SkipScopes, scope) {
+			return
+		}
+
+		key := entryKey{section: section, typ: typ, scope: scope, message: normalizeMessage(message)}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		entry := ReleaseEntry{
+			Scope:   scope,
+			Message: message,
+			Hash:    commit.Hash,
+			Issues:  issueReferences(message, commit, cfg),
+		}
+
+		if cfg.CommitURL != "" && commit.Hash != "" {
+			entry.CommitURL, _ = executeTemplate(cfg.CommitURL,
+				commitTemplateData{Hash: commit.Hash, ShortHash: commit.ShortHash()})
+		}
+
+		sections[section] = append(sections[section], entry)
+
+		if commit.Author != "" {
+			contributors[commit.Author] = commit
+		}
+	}
+
+	for _, commit := range slices.Backward(resolveCommits(commits)) {
+		if r, ok := parseRevert(commit); ok {
+			if section, ok := cfg.section("revert"); ok && !cfg.skipType("revert") {
+				scope, message := r.entry()
+				addEntry(section, "revert", scope, message, commit)
+			}
+			continue
+		}
+
+		cc, ok := parseConventional(commit)
+		if !ok {
+			continue
+		}
+
+		if cfg.skipType(cc.Type) {
+			continue
+		}
+
+		if cc.Breaking {
+			addEntry(sectionBreaking, cc.Type, cc.Scope, cc.BreakingNote, commit)
+		}
+
+		if section, ok := cfg.section(cc.Type); ok {
+			addEntry(section, cc.Type, cc.Scope, cc.Description, commit)
+		}
+	}
+
+	release := Release{
+		Version: tag,
+		Date:    date,
+	}
+
+	for _, name := range cfg.sectionOrder(slices.Collect(maps.Keys(sections))) {
+		entries := sections[name]
+		slices.SortStableFunc(entries, func(a, b ReleaseEntry) int {
+			return cmp.Compare(a.Scope, b.Scope)
+		})
+		release.Sections = append(release.Sections, ReleaseSection{
+			Name:    name,
+			Entries: entries,
+		})
+	}
+
+	if cfg.Contributor != "" {
+		for _, name := range slices.Sorted(maps.Keys(contributors)) {
+			c, err := executeTemplate(cfg.Contributor,
+				contributorTemplateData{Name: name, Email: contributors[name].Email})
+			if err == nil {
+				release.Contributors = append(release.Contributors, c)
+			}
+		}
+	}
+
+	return release
+}
+
+// normalizeMessage returns message in lower case, with white space collapsed
+// and a trailing period removed, for finding duplicate entries.
+func normalizeMessage(message string) string {
+
+	return strings.TrimSuffix(strings.ToLower(strings.Join(strings.Fields(message), " ")), ".")
+}
+
+// issueReferences returns the issues referenced in message (for example
+// "fix crash (#12)") and in the footers of commit (for example
+// "Closes: #45").
+func issueReferences(message string, commit Commit, cfg ChangelogConfig) []ReleaseIssue {
+
+	var ids []string
+
+	for _, m := range reIssueReference.FindAllStringSubmatch(message, -1) {
+		ids = append(ids, m[1])
+	}
+
+	for _, f := range commit.Footers {
+		if f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE" {
+			continue
+		}
+		for _, m := range reIssueReference.FindAllStringSubmatch(f.Value, -1) {
+			ids = append(ids, m[1])
+		}
+	}
+
+	var issues []ReleaseIssue
+	for _, id := range ids {
+		if slices.ContainsFunc(issues, func(i ReleaseIssue) bool { return i.ID == id }) {
+			continue
+		}
+
+		issue := ReleaseIssue{ID: id}
+		if cfg.IssueURL != "" {
+			issue.URL, _ = executeTemplate(cfg.IssueURL, issueTemplateData{Issue: id})
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
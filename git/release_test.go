@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestNewRelease(t *testing.T) {
+	date := time.Date(2025, 11, 19, 12, 0, 0, 0, time.UTC)
+
+	// newest first, like Repository.Log returns them
+	commits := commitsFromMessages(
+		"fix(xsql): second fix",
+		"feat: unscoped feature",
+		"fix(cmd): fix in cmd",
+		"fix(xsql): first fix",
+		"feat(api): first feature",
+		"fix(internal): not shown",
+	)
+
+	cfg := DefaultChangelogConfig()
+	cfg.SkipScopes = []string{"internal"}
+
+	exp := Release{
+		Version: "v1.2.0",
+		Date:    date,
+		Sections: []ReleaseSection{
+			{
+				Name: "Added",
+				Entries: []ReleaseEntry{
+					{Message: "unscoped feature"},
+					{Scope: "api", Message: "first feature"},
+				},
+			},
+			{
+				Name: "Fixed",
+				Entries: []ReleaseEntry{
+					{Scope: "cmd", Message: "fix in cmd"},
+					{Scope: "xsql", Message: "first fix"},
+					{Scope: "xsql", Message: "second fix"},
+				},
+			},
+		},
+	}
+
+	for range 50 {
+		// rendering must be deterministic
+		xt.Eq(t, exp, NewRelease("v1.2.0", date, commits, cfg))
+	}
+
+	t.Run("duplicates", func(t *testing.T) {
+		commits := commitsFromMessages(
+			"feat(xmaps): cache",
+			"feat(xmaps): Cache.",
+			"fix(xmaps): cache",
+			"feat(xmaps): cache invalidation",
+			"feat: cache",
+		)
+
+		exp := []ReleaseSection{
+			{
+				Name: "Added",
+				Entries: []ReleaseEntry{
+					{Message: "cache"},
+					{Scope: "xmaps", Message: "cache invalidation"},
+					{Scope: "xmaps", Message: "Cache."}, // first committed is kept
+				},
+			},
+			{
+				Name:    "Fixed",
+				Entries: []ReleaseEntry{{Scope: "xmaps", Message: "cache"}},
+			},
+		}
+		xt.Eq(t, exp, NewRelease("v1.2.0", date, commits, cfg).Sections)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		xt.Assert(t, NewRelease("v1.2.0", date, commitsFromMessages("Update README"), cfg).IsEmpty())
+	})
+}
+
+func TestNewRelease_enrichment(t *testing.T) {
+	date := time.Date(2025, 11, 19, 12, 0, 0, 0, time.UTC)
+
+	repo := NewMemoryRepository()
+	c1 := repo.Commit(Commit{Subject: "fix(api): crash on empty input (#12)", Author: "Bob", Email: "bob@example.com",
+		Footers: []Footer{{Token: "Closes", Value: "#45, #12"}}})
+	c2 := repo.Commit(Commit{Subject: "feat: add Foo", Author: "Alice", Email: "alice@example.com"})
+	repo.Commit(Commit{Subject: "feat: add Bar", Author: "Bob", Email: "bob@example.com"})
+
+	commits, err := repo.Log("", "HEAD")
+	xt.OK(t, err)
+
+	cfg := DefaultChangelogConfig()
+	cfg.CommitURL = "https://git.example.com/o/r/-/commit/{{.ShortHash}}"
+	cfg.IssueURL = "https://git.example.com/o/r/-/issues/{{.Issue}}"
+	cfg.Contributor = "{{.Name}} <{{.Email}}>"
+
+	release := NewRelease("v1.2.0", date, commits, cfg)
+
+	xt.Eq(t, []string{"Alice <alice@example.com>", "Bob <bob@example.com>"}, release.Contributors)
+
+	added := release.Sections[0].Entries
+	xt.Eq(t, c2.Hash, added[0].Hash)
+	xt.Eq(t, "https://git.example.com/o/r/-/commit/"+c2.ShortHash(), added[0].CommitURL)
+	xt.Eq(t, 0, len(added[0].Issues))
+
+	fixed := release.Sections[1].Entries
+	xt.Eq(t, []ReleaseIssue{
+		{ID: "12", URL: "https://git.example.com/o/r/-/issues/12"},
+		{ID: "45", URL: "https://git.example.com/o/r/-/issues/45"},
+	}, fixed[0].Issues)
+	xt.Eq(t, c1.Hash, fixed[0].Hash)
+
+	t.Run("not enriched", func(t *testing.T) {
+		release := NewRelease("v1.2.0", date, commits, DefaultChangelogConfig())
+		xt.Eq(t, 0, len(release.Contributors))
+		fixed := release.Sections[1].Entries
+		xt.Eq(t, "", fixed[0].CommitURL)
+		xt.Eq(t, []ReleaseIssue{{ID: "12"}, {ID: "45"}}, fixed[0].Issues)
+	})
+}
+
+func TestNewRelease_reverts(t *testing.T) {
+	date := time.Date(2025, 11, 19, 12, 0, 0, 0, time.UTC)
+
+	repo := NewMemoryRepository()
+	released := repo.CommitMessage("feat(xsql): add DSN masking")
+	xt.OK(t, repo.Tag("v1.1.0"))
+	added := repo.CommitMessage("feat: add Foo")
+	repo.CommitMessage("fix(api): handle empty input")
+	repo.CommitMessage("Revert \"feat: add Foo\"\n\nThis reverts commit " + added.Hash + ".")
+	repo.CommitMessage("revert(api): handle empty input")
+	repo.CommitMessage("revert: feat(xsql): add DSN masking\n\nRefs: " + released.ShortHash())
+	repo.CommitMessage("fix: crash on start")
+
+	commits, err := repo.Log("v1.1.0", "HEAD")
+	xt.OK(t, err)
+
+	exp := Release{
+		Version: "v1.2.0",
+		Date:    date,
+		Sections: []ReleaseSection{
+			{
+				Name:    "Fixed",
+				Entries: []ReleaseEntry{{Message: "crash on start"}},
+			},
+			{
+				Name:    "Reverted",
+				Entries: []ReleaseEntry{{Scope: "xsql", Message: "add DSN masking"}},
+			},
+		},
+	}
+
+	release := NewRelease("v1.2.0", date, commits, DefaultChangelogConfig())
+	for i := range release.Sections {
+		for j := range release.Sections[i].Entries {
+			release.Sections[i].Entries[j].Hash = ""
+		}
+	}
+	xt.Eq(t, exp, release)
+
+	t.Run("reverted feature does not bump minor", func(t *testing.T) {
+		xt.Eq(t, BumpPatch, ResolveBump("v1.1.0", commits))
+	})
+
+	t.Run("omitted", func(t *testing.T) {
+		cfg := DefaultChangelogConfig()
+		cfg.SkipTypes = []string{"revert"}
+		release := NewRelease("v1.2.0", date, commits, cfg)
+		xt.Eq(t, 1, len(release.Sections))
+		xt.Eq(t, "Fixed", release.Sections[0].Name)
+	})
+}
+
+func TestNewRelease_squashMerge(t *testing.T) {
+	date := time.Date(2025, 11, 19, 12, 0, 0, 0, time.UTC)
+
+	commits := commitsFromMessages(
+		"feat: add LRU cache (#40)\n\n"+
+			"* feat(xmaps): add LRU\n\n"+
+			"* fix(xmaps): evict oldest entry first\n\n"+
+			"* docs: document LRU\n\n"+
+			"Co-authored-by: Bob <bob@example.com>",
+		"fix: single commit\n\n- feat: only one listed, not expanded",
+	)
+
+	release := NewRelease("v1.2.0", date, commits, DefaultChangelogConfig())
+
+	exp := []ReleaseSection{
+		{
+			Name:    "Added",
+			Entries: []ReleaseEntry{{Scope: "xmaps", Message: "add LRU"}},
+		},
+		{
+			Name:    "Changed",
+			Entries: []ReleaseEntry{{Message: "document LRU"}},
+		},
+		{
+			Name: "Fixed",
+			Entries: []ReleaseEntry{
+				{Message: "single commit"},
+				{Scope: "xmaps", Message: "evict oldest entry first"},
+			},
+		},
+	}
+	xt.Eq(t, exp, release.Sections)
+}
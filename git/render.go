@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Renderer renders a Release into a specific format.
+type Renderer interface {
+	Render(w io.Writer, release Release) error
+}
+
+// RendererFormats are the formats accepted by NewRenderer.
+var RendererFormats = []string{"markdown", "json", "text"}
+
+// NewRenderer returns the Renderer for format, which is one of
+// RendererFormats ("md" is accepted as alias for "markdown").
+func NewRenderer(format string) (Renderer, error) {
+
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return MarkdownRenderer{}, nil
+	case "json":
+		return JSONRenderer{Indent: "  "}, nil
+	case "text":
+		return TextRenderer{}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %q (must be one of %s)",
+		format, strings.Join(RendererFormats, ", "))
+}
+
+// MarkdownRenderer renders a Release as section of a Keep a Changelog document.
+type MarkdownRenderer struct{}
+
+var _ Renderer = MarkdownRenderer{}
+
+// Render writes release as Markdown to w.
+func (MarkdownRenderer) Render(w io.Writer, release Release) error {
+
+	bw := bufio.NewWriter(w)
+
+	// module prefix, like "xgrpc/", is not part of the version
+	_, version := splitTag(release.Version)
+	if version == unreleased {
+		_, _ = fmt.Fprintf(bw, "## [%s]\n\n", unreleased)
+	} else {
+		_, _ = fmt.Fprintf(bw, "## [%s] - %s\n\n",
+			strings.TrimPrefix(version, "v"), release.Date.Format(time.DateOnly))
+	}
+
+	for _, section := range release.Sections {
+		_, _ = fmt.Fprintf(bw, "### %s\n\n", section.Name)
+
+		for _, group := range groupByScope(section.Entries) {
+			switch {
+			case group[0].Scope == "":
+				for _, entry := range group {
+					_, _ = fmt.Fprintf(bw, "- %s\n", markdownEntry(entry))
+				}
+			case len(group) == 1:
+				_, _ = fmt.Fprintf(bw, "- **%s**: %s\n", group[0].Scope, markdownEntry(group[0]))
+			default:
+				_, _ = fmt.Fprintf(bw, "- **%s**:\n", group[0].Scope)
+				for _, entry := range group {
+					_, _ = fmt.Fprintf(bw, "    - %s\n", markdownEntry(entry))
+				}
+			}
+		}
+
+		_, _ = bw.WriteString("\n")
+	}
+
+	if len(release.Contributors) > 0 {
+		_, _ = bw.WriteString("### Contributors\n\n")
+		for _, c := range release.Contributors {
+			_, _ = fmt.Fprintf(bw, "- %s\n", c)
+		}
+		_, _ = bw.WriteString("\n")
+	}
+
+	return bw.Flush()
+}
+
+// markdownEntry returns the message of entry with issue references
+// linked and, when available, the link to the commit appended.
+func markdownEntry(entry ReleaseEntry) string {
+
+	issueLink := func(issue ReleaseIssue) string {
+		if issue.URL == "" {
+			return "#" + issue.ID
+		}
+		return fmt.Sprintf("[#%s](%s)", issue.ID, issue.URL)
+	}
+
+	var b strings.Builder
+	var inline []string
+
+	last := 0
+	for _, m := range reIssueReference.FindAllStringSubmatchIndex(entry.Message, -1) {
+		start, end := m[2]-1, m[3] // including '#'
+		id := entry.Message[m[2]:m[3]]
+		inline = append(inline, id)
+
+		b.WriteString(entry.Message[last:start])
+		if i := slices.IndexFunc(entry.Issues, func(i ReleaseIssue) bool { return i.ID == id }); i != -1 {
+			b.WriteString(issueLink(entry.Issues[i]))
+		} else {
+			b.WriteString(entry.Message[start:end])
+		}
+		last = end
+	}
+	b.WriteString(entry.Message[last:])
+
+	var refs []string
+	for _, issue := range entry.Issues {
+		if !slices.Contains(inline, issue.ID) {
+			refs = append(refs, issueLink(issue))
+		}
+	}
+	if len(refs) > 0 {
+		b.WriteString(" (" + strings.Join(refs, ", ") + ")")
+	}
+
+	if entry.CommitURL != "" {
+		_, _ = fmt.Fprintf(&b, " ([%s](%s))", entry.ShortHash(), entry.CommitURL)
+	}
+
+	return b.String()
+}
+
+// JSONRenderer renders a Release as JSON object, for example, to be
+// consumed by release tooling.
+type JSONRenderer struct {
+	// Indent, when not empty, is used to indent the JSON output.
+	Indent string
+}
+
+var _ Renderer = JSONRenderer{}
+
+// Render writes release as JSON to w.
+func (r JSONRenderer) Render(w io.Writer, release Release) error {
+
+	if release.Sections == nil {
+		release.Sections = []ReleaseSection{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", r.Indent)
+
+	return enc.Encode(release)
+}
+
+// RenderAll writes releases as a single JSON array to w, rendering each
+// release like Render does.
+func (r JSONRenderer) RenderAll(w io.Writer, releases []Release) error {
+
+	all := make([]Release, 0, len(releases))
+	for _, release := range releases {
+		if release.Sections == nil {
+			release.Sections = []ReleaseSection{}
+		}
+		all = append(all, release)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", r.Indent)
+
+	return enc.Encode(all)
+}
+
+// TextRenderer renders a Release as plain text without heading, for example,
+// to be used as body of a GitHub or GitLab release.
+type TextRenderer struct{}
+
+var _ Renderer = TextRenderer{}
+
+// Render writes release as plain text to w.
+func (TextRenderer) Render(w io.Writer, release Release) error {
+
+	bw := bufio.NewWriter(w)
+
+	for i, section := range release.Sections {
+		if i > 0 {
+			_, _ = bw.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(bw, "%s:\n", section.Name)
+
+		for _, entry := range section.Entries {
+			message := entry.Message
+			for _, issue := range entry.Issues {
+				if !strings.Contains(message, "#"+issue.ID) {
+					message += " (#" + issue.ID + ")"
+				}
+			}
+
+			if entry.Scope != "" {
+				_, _ = fmt.Fprintf(bw, "- %s: %s\n", entry.Scope, message)
+			} else {
+				_, _ = fmt.Fprintf(bw, "- %s\n", message)
+			}
+		}
+	}
+
+	if len(release.Contributors) > 0 {
+		if len(release.Sections) > 0 {
+			_, _ = bw.WriteString("\n")
+		}
+		_, _ = bw.WriteString("Contributors:\n")
+		for _, c := range release.Contributors {
+			_, _ = fmt.Fprintf(bw, "- %s\n", c)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// groupByScope groups consecutive entries having the same scope.
+func groupByScope(entries []ReleaseEntry) [][]ReleaseEntry {
+
+	var groups [][]ReleaseEntry
+
+	for _, e := range entries {
+		if n := len(groups); n > 0 && groups[n-1][0].Scope == e.Scope {
+			groups[n-1] = append(groups[n-1], e)
+			continue
+		}
+		groups = append(groups, []ReleaseEntry{e})
+	}
+
+	return groups
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+var testRelease = Release{
+	Version: "v1.2.0",
+	Date:    time.Date(2025, 11, 19, 12, 0, 0, 0, time.UTC),
+	Sections: []ReleaseSection{
+		{
+			Name: "Added",
+			Entries: []ReleaseEntry{
+				{Message: "unscoped feature"},
+				{Scope: "api", Message: "first feature"},
+			},
+		},
+		{
+			Name: "Fixed",
+			Entries: []ReleaseEntry{
+				{Scope: "cmd", Message: "fix in cmd"},
+				{Scope: "xsql", Message: "first fix"},
+				{Scope: "xsql", Message: "second fix"},
+			},
+		},
+	},
+}
+
+func render(t *testing.T, r Renderer, release Release) string {
+	t.Helper()
+
+	var b strings.Builder
+	xt.OK(t, r.Render(&b, release))
+	return b.String()
+}
+
+func TestNewRenderer(t *testing.T) {
+	for _, format := range append(RendererFormats, "MD") {
+		t.Run(format, func(t *testing.T) {
+			_, err := NewRenderer(format)
+			xt.OK(t, err)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := NewRenderer("html")
+		xt.KO(t, err)
+		xt.Eq(t, `unsupported format "html" (must be one of markdown, json, text)`, err.Error())
+	})
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	exp := "## [1.2.0] - 2025-11-19\n\n" +
+		"### Added\n\n" +
+		"- unscoped feature\n" +
+		"- **api**: first feature\n\n" +
+		"### Fixed\n\n" +
+		"- **cmd**: fix in cmd\n" +
+		"- **xsql**:\n" +
+		"    - first fix\n" +
+		"    - second fix\n\n"
+
+	xt.Eq(t, exp, render(t, MarkdownRenderer{}, testRelease))
+}
+
+func TestJSONRenderer(t *testing.T) {
+	exp := `{"version":"v1.2.0","date":"2025-11-19T12:00:00Z","sections":[` +
+		`{"name":"Added","entries":[{"message":"unscoped feature"},{"scope":"api","message":"first feature"}]},` +
+		`{"name":"Fixed","entries":[{"scope":"cmd","message":"fix in cmd"},` +
+		`{"scope":"xsql","message":"first fix"},{"scope":"xsql","message":"second fix"}]}]}` + "\n"
+
+	xt.Eq(t, exp, render(t, JSONRenderer{}, testRelease))
+
+	t.Run("empty release", func(t *testing.T) {
+		exp := `{"version":"v1.2.0","date":"0001-01-01T00:00:00Z","sections":[]}` + "\n"
+		xt.Eq(t, exp, render(t, JSONRenderer{}, Release{Version: "v1.2.0"}))
+	})
+
+	t.Run("all releases", func(t *testing.T) {
+		var b strings.Builder
+		xt.OK(t, JSONRenderer{}.RenderAll(&b, []Release{{Version: "v1.2.0"}, {Version: "v1.1.0"}}))
+		xt.Eq(t, `[{"version":"v1.2.0","date":"0001-01-01T00:00:00Z","sections":[]},`+
+			`{"version":"v1.1.0","date":"0001-01-01T00:00:00Z","sections":[]}]`+"\n", b.String())
+	})
+}
+
+func TestTextRenderer(t *testing.T) {
+	exp := "Added:\n" +
+		"- unscoped feature\n" +
+		"- api: first feature\n\n" +
+		"Fixed:\n" +
+		"- cmd: fix in cmd\n" +
+		"- xsql: first fix\n" +
+		"- xsql: second fix\n"
+
+	xt.Eq(t, exp, render(t, TextRenderer{}, testRelease))
+}
+
+func TestMarkdownRenderer_enriched(t *testing.T) {
+	release := Release{
+		Version: "v1.2.0",
+		Date:    time.Date(2025, 11, 19, 12, 0, 0, 0, time.UTC),
+		Sections: []ReleaseSection{
+			{
+				Name: "Fixed",
+				Entries: []ReleaseEntry{
+					{
+						Scope:     "api",
+						Message:   "crash on empty input (#12)",
+						Hash:      "0123456789abcdef",
+						CommitURL: "https://example.com/commit/0123456789abcdef",
+						Issues: []ReleaseIssue{
+							{ID: "12", URL: "https://example.com/issues/12"},
+							{ID: "45", URL: "https://example.com/issues/45"},
+						},
+					},
+					{
+						Scope:   "cmd",
+						Message: "no links",
+						Issues:  []ReleaseIssue{{ID: "7"}},
+					},
+				},
+			},
+		},
+		Contributors: []string{"Alice", "Bob"},
+	}
+
+	exp := "## [1.2.0] - 2025-11-19\n\n" +
+		"### Fixed\n\n" +
+		"- **api**: crash on empty input ([#12](https://example.com/issues/12)) " +
+		"([#45](https://example.com/issues/45)) ([0123456](https://example.com/commit/0123456789abcdef))\n" +
+		"- **cmd**: no links (#7)\n\n" +
+		"### Contributors\n\n" +
+		"- Alice\n" +
+		"- Bob\n\n"
+
+	xt.Eq(t, exp, render(t, MarkdownRenderer{}, release))
+
+	expText := "Fixed:\n" +
+		"- api: crash on empty input (#12) (#45)\n" +
+		"- cmd: no links (#7)\n\n" +
+		"Contributors:\n" +
+		"- Alice\n" +
+		"- Bob\n"
+
+	xt.Eq(t, expText, render(t, TextRenderer{}, release))
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// logFormat is the format used with git-log to retrieve commits. Fields are
+// separated using the unit separator, commits using the record separator.
+const logFormat = "%H%x1f%an%x1f%ae%x1f%aI%x1f%B%x1e"
+
+// Repository gives access to the history of a Git repository.
+type Repository interface {
+	// LatestTag returns the most recent tag reachable from branch. When
+	// branch is empty, "main" is used. Only tags starting with prefix and
+	// not containing further slashes are considered; when prefix is empty,
+	// tags containing a slash (for example, tags of nested modules) are ignored.
+	LatestTag(branch, prefix string) (string, error)
+	// Log returns the commits reachable from revision to, but not from
+	// revision from, newest first. When from is empty, all commits reachable
+	// from to are returned. When paths are provided, only commits changing
+	// files within those paths are returned.
+	Log(from, to string, paths ...string) ([]Commit, error)
+	// Tags returns the tags being a semantic version, oldest (lowest
+	// version) first. Tags are filtered using prefix like LatestTag does.
+	Tags(prefix string) ([]Tag, error)
+}
+
+// Tag is a tag of a Repository.
+type Tag struct {
+	// Name is the name of the tag, for example "v1.2.0" or "xgrpc/v1.2.0".
+	Name string
+	// Hash is the hash of the tagged commit.
+	Hash string
+	// Date is the commit date of the tagged commit.
+	Date time.Time
+}
+
+// tagFormat is the format used with git-for-each-ref to retrieve tags. The
+// hash and date of annotated tags are found by dereferencing (*) the tag.
+const tagFormat = "%(refname:strip=2)%1f%(objectname)%1f%(committerdate:iso-strict)%1f" +
+	"%(*objectname)%1f%(*committerdate:iso-strict)"
+
+// ExecRepository is a Repository executing the git command.
+type ExecRepository struct {
+	// Dir is the working directory in which git is executed. When empty,
+	// the current working directory is used.
+	Dir string
+	// Git is the path to the git binary. When empty, "git" is looked
+	// up in PATH.
+	Git string
+	// Env are additional environment variables, in the form "key=value",
+	// passed to git on top of the environment of the current process.
+	Env []string
+}
+
+var _ Repository = (*ExecRepository)(nil)
+
+// NewExecRepository returns a new ExecRepository executing git within dir.
+func NewExecRepository(dir string) *ExecRepository {
+
+	return &ExecRepository{Dir: dir}
+}
+
+// run executes git with args and returns what was written to standard output.
+// The error includes what was written to standard error.
+func (r *ExecRepository) run(args ...string) (string, error) {
+
+	bin := r.Git
+	if bin == "" {
+		bin = "git"
+	}
+
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = r.Dir
+	if len(r.Env) > 0 {
+		cmd.Env = append(os.Environ(), r.Env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %w (%s)", args[0], err, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.String(), nil
+}
+
+// LatestTag returns the most recent tag reachable from branch using git-describe.
+// When branch is empty, "main" is used. See Repository for how prefix is used.
+func (r *ExecRepository) LatestTag(branch, prefix string) (string, error) {
+
+	if strings.TrimSpace(branch) == "" {
+		branch = "main"
+	}
+
+	args := []string{"describe", "--tags", "--abbrev=0"}
+	if prefix != "" {
+		args = append(args, "--match", prefix+"*")
+	}
+	args = append(args, "--exclude", prefix+"*/*", branch)
+
+	out, err := r.run(args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Log returns the commits reachable from to, but not from from, newest first.
+// When paths are provided, only commits changing files within these paths
+// are returned.
+func (r *ExecRepository) Log(from, to string, paths ...string) ([]Commit, error) {
+
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+
+	args := append([]string{"log", "--no-decorate", "--format=" + logFormat, revRange, "--"}, paths...)
+
+	out, err := r.run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLog(out)
+}
+
+// CreateTag creates the annotated tag name for revision rev using message.
+// When rev is empty, HEAD is tagged. The message is used verbatim, that is,
+// lines starting with '#' are kept.
+func (r *ExecRepository) CreateTag(name, rev, message string) error {
+
+	args := []string{"tag", "--annotate", "--cleanup=verbatim", "--message", message, name}
+	if rev != "" {
+		args = append(args, rev)
+	}
+
+	_, err := r.run(args...)
+	return err
+}
+
+// Tags returns the tags being a semantic version, oldest first. See
+// Repository for how prefix is used.
+func (r *ExecRepository) Tags(prefix string) ([]Tag, error) {
+
+	out, err := r.run("for-each-ref", "--format="+tagFormat, "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []Tag
+
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected git for-each-ref line %q", line)
+		}
+
+		hash, date := fields[1], fields[2]
+		if fields[3] != "" {
+			// annotated tag
+			hash, date = fields[3], fields[4]
+		}
+
+		tag := Tag{Name: fields[0], Hash: hash}
+		if tag.Date, err = time.Parse(time.RFC3339, date); err != nil {
+			return nil, fmt.Errorf("tag %s: %w", tag.Name, err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return sortTags(tags, prefix), nil
+}
+
+// sortTags returns the tags starting with prefix, without further slashes,
+// and being a semantic version, sorted by version (lowest first).
+func sortTags(tags []Tag, prefix string) []Tag {
+
+	tags = slices.DeleteFunc(tags, func(t Tag) bool {
+		rest, ok := strings.CutPrefix(t.Name, prefix)
+		return !ok || strings.Contains(rest, "/") || !semver.IsValid(ensureV(rest))
+	})
+
+	slices.SortStableFunc(tags, func(a, b Tag) int {
+		_, va := splitTag(a.Name)
+		_, vb := splitTag(b.Name)
+		return semver.Compare(ensureV(va), ensureV(vb))
+	})
+
+	return tags
+}
+
+// parseLog parses the output of git-log using logFormat.
+func parseLog(out string) ([]Commit, error) {
+
+	commits := []Commit{}
+
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x1f", 5)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected git log record %q", record)
+		}
+
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("commit %s: %w", fields[0], err)
+		}
+
+		c := ParseCommitMessage(fields[4])
+		c.RawMessage = fields[4]
+		c.Hash = fields[0]
+		c.Author = fields[1]
+		c.Email = fields[2]
+		c.Date = date
+
+		commits = append(commits, c)
+	}
+
+	return commits, nil
+}
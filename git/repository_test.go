@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+// newTestExecRepository creates a Git repository in a temporary directory
+// committing each of the messages (in order). The commit at index n is
+// tagged when tags contains n.
+func newTestExecRepository(t *testing.T, messages []string, tags map[int]string) *ExecRepository {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	repo := &ExecRepository{
+		Dir: dir,
+		Env: []string{
+			"GIT_CONFIG_GLOBAL=/dev/null",
+			"GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=Alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=Alice", "GIT_COMMITTER_EMAIL=alice@example.com",
+		},
+	}
+
+	_, err := repo.run("init", "--initial-branch=main")
+	xt.OK(t, err)
+
+	for i, msg := range messages {
+		xt.OK(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(msg), 0o644))
+		_, err := repo.run("add", "file.txt")
+		xt.OK(t, err)
+		_, err = repo.run("commit", "-q", "-m", msg)
+		xt.OK(t, err)
+		if tag, ok := tags[i]; ok {
+			_, err := repo.run("tag", tag)
+			xt.OK(t, err)
+		}
+	}
+
+	return repo
+}
+
+func TestExecRepository(t *testing.T) {
+	repo := newTestExecRepository(t,
+		[]string{"feat: first", "fix: second", "feat(api): third\n\nBREAKING CHANGE: gone"},
+		map[int]string{0: "v1.0.0"})
+
+	tag, err := repo.LatestTag("", "")
+	xt.OK(t, err)
+	xt.Eq(t, "v1.0.0", tag)
+
+	commits, err := repo.Log(tag, "HEAD")
+	xt.OK(t, err)
+	xt.Eq(t, 2, len(commits))
+	xt.Eq(t, "feat(api): third", commits[0].Subject)
+	xt.Eq(t, "Alice", commits[0].Author)
+	xt.Eq(t, 40, len(commits[0].Hash))
+	note, ok := commits[0].BreakingChange()
+	xt.Assert(t, ok)
+	xt.Eq(t, "gone", note)
+	xt.Eq(t, "fix: second", commits[1].Subject)
+
+	t.Run("all commits", func(t *testing.T) {
+		commits, err := repo.Log("", "HEAD")
+		xt.OK(t, err)
+		xt.Eq(t, 3, len(commits))
+	})
+
+	t.Run("error includes stderr", func(t *testing.T) {
+		_, err := repo.Log("v9.9.9", "HEAD")
+		xt.KO(t, err)
+		xt.MatchString(t, `(?s)^git log: exit status \d+ \(.*v9\.9\.9.*\)$`, err.Error())
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		xt.OK(t, repo.CreateTag("v1.1.0", "", "v1.1.0\n\n# Added\n- third"))
+		xt.KO(t, repo.CreateTag("v1.1.0", "", "again"))
+
+		msg, err := repo.run("tag", "--list", "--format=%(contents)", "v1.1.0")
+		xt.OK(t, err)
+		xt.Eq(t, "v1.1.0\n\n# Added\n- third\n", msg)
+
+		_, err = repo.run("tag", "not-a-version", "HEAD~1")
+		xt.OK(t, err)
+		_, err = repo.run("tag", "xgrpc/v0.1.0", "HEAD~1")
+		xt.OK(t, err)
+
+		tags, err := repo.Tags("")
+		xt.OK(t, err)
+		xt.Eq(t, 2, len(tags))
+		xt.Eq(t, "v1.0.0", tags[0].Name)
+		xt.Eq(t, "v1.1.0", tags[1].Name)
+		xt.Eq(t, commits[0].Hash, tags[1].Hash) // annotated tag dereferenced
+		xt.Assert(t, !tags[1].Date.IsZero())
+
+		tags, err = repo.Tags("xgrpc/")
+		xt.OK(t, err)
+		xt.Eq(t, 1, len(tags))
+		xt.Eq(t, "xgrpc/v0.1.0", tags[0].Name)
+		xt.Eq(t, commits[1].Hash, tags[0].Hash)
+	})
+
+	t.Run("git binary not found", func(t *testing.T) {
+		repo := &ExecRepository{Dir: repo.Dir, Git: "/no/such/git"}
+		_, err := repo.LatestTag("main", "")
+		xt.KO(t, err)
+	})
+}
+
+func TestParseLog(t *testing.T) {
+	out := "0123456789abcdef\x1fAlice\x1falice@example.com\x1f2025-11-19T10:00:00+01:00\x1f" +
+		"fix: one\n\nBody.\n\x1e\n" +
+		"fedcba9876543210\x1fBob\x1fbob@example.com\x1f2025-11-18T10:00:00Z\x1ffeat: two\n\x1e\n"
+
+	commits, err := parseLog(out)
+	xt.OK(t, err)
+	xt.Eq(t, 2, len(commits))
+
+	xt.Eq(t, "0123456789abcdef", commits[0].Hash)
+	xt.Eq(t, "0123456", commits[0].ShortHash())
+	xt.Eq(t, "Alice", commits[0].Author)
+	xt.Eq(t, "alice@example.com", commits[0].Email)
+	xt.Assert(t, commits[0].Date.Equal(time.Date(2025, 11, 19, 9, 0, 0, 0, time.UTC)))
+	xt.Eq(t, "fix: one", commits[0].Subject)
+	xt.Eq(t, "Body.", commits[0].Body)
+	xt.Eq(t, "fix: one\n\nBody.\n", commits[0].RawMessage)
+
+	xt.Eq(t, "feat: two", commits[1].Subject)
+
+	t.Run("empty", func(t *testing.T) {
+		commits, err := parseLog("")
+		xt.OK(t, err)
+		xt.Eq(t, 0, len(commits))
+	})
+}
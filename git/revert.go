@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// sectionReverted is the name of the section listing reverted changes
+// which were released before.
+const sectionReverted = "Reverted"
+
+var reRevertSubject = regexp.MustCompile(`^Revert "(.+)"$`)
+var reRevertsCommit = regexp.MustCompile(`(?m)^This reverts commit ([0-9a-fA-F]{4,40})`)
+var reRevertHash = regexp.MustCompile(`(?:^|[\s,])([0-9a-fA-F]{7,40})\b`)
+var reListItem = regexp.MustCompile(`^(?:[*-]\s+)?(.+)$`)
+
+// revert holds what a revert commit reverts.
+type revert struct {
+	// Subject is the subject of the reverted commit, when known.
+	Subject string
+	// Scope and Description identify the reverted commit when the revert
+	// commit uses the Conventional Commits form without the complete
+	// subject, for example "revert(api): add Foo".
+	Scope       string
+	Description string
+	// Hashes are the (possibly abbreviated) hashes of the reverted commits.
+	Hashes []string
+}
+
+// parseRevert returns what c reverts, and whether c is a revert commit.
+// Both the message produced by "git revert", for example:
+//
+//	Revert "feat: add Foo"
+//
+//	This reverts commit 0123456789abcdef0123456789abcdef01234567.
+//
+// and the Conventional Commits form, for example "revert: feat: add Foo"
+// with a footer like "Refs: 0123456", are supported.
+func parseRevert(c Commit) (revert, bool) {
+
+	var r revert
+
+	switch m := reRevertSubject.FindStringSubmatch(c.Subject); {
+	case m != nil:
+		r.Subject = m[1]
+	default:
+		cc, ok := parseConventional(c)
+		if !ok || cc.Type != "revert" {
+			return revert{}, false
+		}
+		if reConventionalCommit.MatchString(cc.Description) {
+			r.Subject = cc.Description
+		} else {
+			r.Scope, r.Description = cc.Scope, cc.Description
+		}
+	}
+
+	for _, m := range reRevertsCommit.FindAllStringSubmatch(c.Body, -1) {
+		r.Hashes = append(r.Hashes, strings.ToLower(m[1]))
+	}
+
+	for _, f := range c.Footers {
+		if !strings.EqualFold(f.Token, "Refs") && !strings.EqualFold(f.Token, "Reverts") {
+			continue
+		}
+		for _, m := range reRevertHash.FindAllStringSubmatch(f.Value, -1) {
+			r.Hashes = append(r.Hashes, strings.ToLower(m[1]))
+		}
+	}
+
+	return r, true
+}
+
+// reverts returns whether r reverts c.
+func (r revert) reverts(c Commit) bool {
+
+	if slices.ContainsFunc(r.Hashes, func(h string) bool {
+		return c.Hash != "" && strings.HasPrefix(strings.ToLower(c.Hash), h)
+	}) {
+		return true
+	}
+
+	if r.Subject != "" {
+		return c.Subject == r.Subject
+	}
+
+	cc, ok := parseConventional(c)
+	return ok && cc.Scope == r.Scope && cc.Description == r.Description
+}
+
+// entry returns the scope and message documenting r in the section
+// listing reverted changes.
+func (r revert) entry() (string, string) {
+
+	if r.Subject == "" {
+		return r.Scope, r.Description
+	}
+
+	if cc, ok := parseConventional(Commit{Subject: r.Subject}); ok {
+		return cc.Scope, cc.Description
+	}
+
+	return "", r.Subject
+}
+
+// expandSquash returns the commits listed in the body of the squash-merge
+// commit c, for example, as created by GitHub:
+//
+//	feat: add LRU cache (#40)
+//
+//	* feat(xmaps): add LRU
+//
+//	* fix(xmaps): evict oldest entry first
+//
+// Each line of the body being a Conventional Commits subject, optionally
+// as list item, becomes a separate commit sharing hash, author, date, and
+// footers with c. The "BREAKING CHANGE" footer is only kept for the first.
+// When the body lists fewer than two subjects, c is returned as is.
+func expandSquash(c Commit) []Commit {
+
+	var subjects []string
+	for _, line := range strings.Split(c.Body, "\n") {
+		m := reListItem.FindStringSubmatch(strings.TrimSpace(line))
+		if m != nil && reConventionalCommit.MatchString(m[1]) {
+			subjects = append(subjects, m[1])
+		}
+	}
+
+	if len(subjects) < 2 {
+		return []Commit{c}
+	}
+
+	var footers []Footer
+	for _, f := range c.Footers {
+		if f.Token != "BREAKING CHANGE" && f.Token != "BREAKING-CHANGE" {
+			footers = append(footers, f)
+		}
+	}
+
+	commits := make([]Commit, len(subjects))
+	for i, subject := range subjects {
+		commits[i] = c
+		commits[i].Subject = subject
+		commits[i].Body = ""
+		if i > 0 {
+			commits[i].Footers = footers
+		}
+	}
+
+	return commits
+}
+
+// resolveCommits expands squash-merge commits (see expandSquash), and drops
+// reverted commits together with the commit reverting them when both are
+// part of commits. Reverts of commits not part of commits, for example
+// because they were released before, are kept. When such a commit reverts
+// a revert, it is kept as the original change.
+//
+// Like commits, as returned by Repository.Log, the result is newest first.
+func resolveCommits(commits []Commit) []Commit {
+
+	var kept []Commit // oldest first
+
+	for _, commit := range slices.Backward(commits) {
+		r, ok := parseRevert(commit)
+		if !ok {
+			kept = append(kept, expandSquash(commit)...)
+			continue
+		}
+
+		// the most recent matching commit is reverted; when matched by hash,
+		// all commits expanded from a squash-merge are
+		reverted := false
+		for i := len(kept) - 1; i >= 0; i-- {
+			if r.reverts(kept[i]) {
+				hash := kept[i].Hash
+				kept = slices.Delete(kept, i, i+1)
+				reverted = true
+				if hash != "" && slices.ContainsFunc(r.Hashes, func(h string) bool { return strings.HasPrefix(hash, h) }) {
+					kept = slices.DeleteFunc(kept, func(c Commit) bool { return c.Hash == hash })
+				}
+				break
+			}
+		}
+
+		if !reverted {
+			if inner, ok := parseRevert(Commit{Subject: r.Subject}); ok && inner.Subject != "" {
+				// reverting a revert reintroduces the original change
+				commit.Subject, commit.Body = inner.Subject, ""
+			}
+			kept = append(kept, commit)
+		}
+	}
+
+	slices.Reverse(kept)
+
+	return kept
+}
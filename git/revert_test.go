@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestParseRevert(t *testing.T) {
+	cases := map[string]struct {
+		msg string
+		exp revert
+	}{
+		"git revert": {
+			msg: "Revert \"feat: add Foo\"\n\nThis reverts commit 0123456789ABCDEF0123456789abcdef01234567.",
+			exp: revert{Subject: "feat: add Foo", Hashes: []string{"0123456789abcdef0123456789abcdef01234567"}},
+		},
+		"conventional with subject": {
+			msg: "revert: feat(api): add Foo\n\nRefs: 676104e, a215868",
+			exp: revert{Subject: "feat(api): add Foo", Hashes: []string{"676104e", "a215868"}},
+		},
+		"conventional with description": {
+			msg: "revert(api): add Foo\n\nRefs: #12",
+			exp: revert{Scope: "api", Description: "add Foo"},
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, ok := parseRevert(ParseCommitMessage(cs.msg))
+			xt.Assert(t, ok)
+			xt.Eq(t, cs.exp, r)
+		})
+	}
+
+	t.Run("not a revert", func(t *testing.T) {
+		for _, msg := range []string{"feat: add Foo", "Reverting is hard", "Revert parts of Foo"} {
+			_, ok := parseRevert(ParseCommitMessage(msg))
+			xt.Assert(t, !ok, msg)
+		}
+	})
+}
+
+func TestResolveCommits(t *testing.T) {
+	t.Run("revert squash-merge by hash", func(t *testing.T) {
+		repo := NewMemoryRepository()
+		squash := repo.CommitMessage("feat: big change (#3)\n\n* feat: one\n* fix: two")
+		repo.CommitMessage("fix: kept")
+		repo.CommitMessage("Revert \"feat: big change (#3)\"\n\nThis reverts commit " + squash.Hash + ".")
+
+		commits, err := repo.Log("", "HEAD")
+		xt.OK(t, err)
+
+		resolved := resolveCommits(commits)
+		xt.Eq(t, 1, len(resolved))
+		xt.Eq(t, "fix: kept", resolved[0].Subject)
+	})
+
+	t.Run("revert of revert", func(t *testing.T) {
+		commits := commitsFromMessages(
+			"Revert \"Revert \"feat: add Foo\"\"",
+			"Revert \"feat: add Foo\"",
+			"feat: add Foo",
+		)
+
+		resolved := resolveCommits(commits)
+		xt.Eq(t, 1, len(resolved))
+		xt.Eq(t, "feat: add Foo", resolved[0].Subject)
+	})
+
+	t.Run("breaking change footer kept once", func(t *testing.T) {
+		c := ParseCommitMessage("feat: big (#3)\n\n* feat: one\n* feat: two\n\nBREAKING CHANGE: gone\nCloses: #3")
+
+		expanded := expandSquash(c)
+		xt.Eq(t, 2, len(expanded))
+		_, ok := expanded[0].BreakingChange()
+		xt.Assert(t, ok)
+		_, ok = expanded[1].BreakingChange()
+		xt.Assert(t, !ok)
+		v, _ := expanded[1].Footer("Closes")
+		xt.Eq(t, "#3", v)
+	})
+}
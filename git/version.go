@@ -0,0 +1,349 @@
+/*
+ * Copyright (c) 2025, 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+var reIdentifiers = regexp.MustCompile(`^[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*$`)
+
+// Bump defines which component of a semantic version is incremented. The
+// zero value is BumpMinor, the default of NextVersion.
+type Bump int
+
+const (
+	BumpMinor Bump = iota
+	BumpMajor
+	BumpPatch
+	// BumpAuto derives the increment from the commits (see ResolveBump).
+	BumpAuto
+)
+
+var bumpNames = map[Bump]string{
+	BumpAuto:  "auto",
+	BumpMajor: "major",
+	BumpMinor: "minor",
+	BumpPatch: "patch",
+}
+
+// String returns the name of b, for example "minor".
+func (b Bump) String() string {
+
+	if n, ok := bumpNames[b]; ok {
+		return n
+	}
+	return fmt.Sprintf("Bump(%d)", int(b))
+}
+
+// ParseBump returns the Bump for name, which is one of auto, major, minor,
+// or patch (case-insensitive).
+func ParseBump(name string) (Bump, error) {
+
+	for b, n := range bumpNames {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return b, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid bump %q (must be auto, major, minor, or patch)", name)
+}
+
+// ResolveBump returns which version component to increment based on the
+// commits made since tag:
+//   - BumpMajor when there is a breaking change,
+//   - BumpMinor when there is a new feature (type feat),
+//   - BumpPatch otherwise, for example when there are only fixes.
+//
+// While the major version of tag is 0 (initial development), a breaking
+// change results in BumpMinor. Reverted commits are not taken into account.
+func ResolveBump(tag string, commits []Commit) Bump {
+
+	bump := BumpPatch
+
+	for _, commit := range resolveCommits(commits) {
+		cc, ok := parseConventional(commit)
+		if !ok {
+			continue
+		}
+
+		if cc.Breaking {
+			bump = BumpMajor
+			break
+		}
+
+		if cc.Type == "feat" {
+			bump = BumpMinor
+		}
+	}
+
+	if bump == BumpMajor {
+		if major, _, _, err := parseVersion(tag); err == nil && major == 0 {
+			bump = BumpMinor
+		}
+	}
+
+	return bump
+}
+
+// NextVersion calculates the next version using golang.org/x/mod/semver.
+// It expects a semantic version tag like 'vMAJOR.MINOR.PATCH'.
+// When hotfix is true, the PATCH component is incremented; otherwise the MINOR
+// component is incremented and PATCH reset to 0. The returned version is a
+// canonical semver starting with 'v'.
+func NextVersion(tag string, hotfix bool) (string, error) {
+
+	if hotfix {
+		return BumpVersion(tag, BumpPatch)
+	}
+	return BumpVersion(tag, BumpMinor)
+}
+
+// BumpVersion increments the component of the semantic version tag as
+// defined by bump. Incrementing MAJOR resets MINOR and PATCH to 0, incrementing
+// MINOR resets PATCH to 0. BumpAuto is not accepted; use ResolveBump first.
+// The returned version is a canonical semver starting with 'v', prefixed
+// with the module path of tag, if any (for example "xgrpc/v1.3.0").
+func BumpVersion(tag string, bump Bump) (string, error) {
+
+	prefix, _ := splitTag(tag)
+
+	major, minor, patch, err := parseVersion(tag)
+	if err != nil {
+		return "", err
+	}
+
+	switch bump {
+	case BumpMajor:
+		major++
+		minor = 0
+		patch = 0
+	case BumpMinor:
+		minor++
+		patch = 0
+	case BumpPatch:
+		patch++
+	default:
+		return "", fmt.Errorf("cannot bump version using %s", bump)
+	}
+
+	next := fmt.Sprintf("v%d.%d.%d", major, minor, patch)
+	// Return canonical just in case
+	return prefix + semver.Canonical(next), nil
+}
+
+// VersionOptions defines how the next version is calculated by NextVersionWith.
+type VersionOptions struct {
+	// Bump defines which component of the version is incremented. The zero
+	// value is BumpMinor. BumpAuto increments PATCH since NextVersionWith has
+	// no commits to resolve it from (see ResolveBump).
+	Bump Bump
+	// Prerelease is the prerelease identifier, for example "rc" or "beta".
+	// When set, a prerelease version is returned (see NextVersionWith).
+	Prerelease string
+	// Build is the build metadata appended to the version, for example "build.42".
+	Build string
+	// Promote turns a prerelease into its final version, for example,
+	// v1.4.0-rc.2 becomes v1.4.0.
+	Promote bool
+}
+
+// NextVersionWith calculates the next version of tag using opts:
+//   - with Promote, the prerelease of tag is dropped: v1.4.0-rc.2 becomes v1.4.0;
+//   - with Prerelease and tag being a prerelease using the same identifier, its
+//     number is incremented: v1.4.0-rc.1 becomes v1.4.0-rc.2;
+//   - with Prerelease and tag being a prerelease using another identifier, a new
+//     series starts for the same version: v1.4.0-beta.3 becomes v1.4.0-rc.1;
+//   - with Prerelease and tag being a final version, Bump is applied and a
+//     new series starts: v1.3.0 becomes v1.4.0-rc.1 (when Bump is BumpMinor);
+//   - with tag being a prerelease of a version which Bump would also result
+//     in, the prerelease is dropped: v1.4.0-rc.2 becomes v1.4.0 using
+//     BumpPatch or BumpMinor, but v2.0.0 using BumpMajor;
+//   - otherwise, Bump is applied like BumpVersion does.
+//
+// When Bump is BumpAuto, PATCH is incremented.
+//
+// Build metadata of tag is dropped; opts.Build, when not empty, is appended
+// to the result. An error is returned when the result is not greater than tag.
+//
+// Tags of modules stored in a subdirectory, like "xgrpc/v1.3.0", keep
+// their prefix: the result would be "xgrpc/v1.4.0".
+func NextVersionWith(tag string, opts VersionOptions) (string, error) {
+
+	if opts.Promote && opts.Prerelease != "" {
+		return "", fmt.Errorf("cannot promote and start prerelease at the same time")
+	}
+
+	if opts.Prerelease != "" && !reIdentifiers.MatchString(opts.Prerelease) {
+		return "", fmt.Errorf("invalid prerelease identifier %q", opts.Prerelease)
+	}
+
+	if opts.Build != "" && !reIdentifiers.MatchString(opts.Build) {
+		return "", fmt.Errorf("invalid build metadata %q", opts.Build)
+	}
+
+	prefix, version := splitTag(tag)
+
+	major, minor, patch, err := parseVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	base := fmt.Sprintf("v%d.%d.%d", major, minor, patch)
+	pre := strings.TrimPrefix(semver.Prerelease(semver.Canonical(ensureV(version))), "-")
+
+	var next string
+
+	switch {
+	case opts.Promote:
+		if pre == "" {
+			return "", fmt.Errorf("cannot promote %q; not a prerelease", tag)
+		}
+		next = base
+	case opts.Prerelease != "" && pre != "":
+		id, n := splitPrerelease(pre)
+		if id != opts.Prerelease {
+			n = 0
+		}
+		next = fmt.Sprintf("%s-%s.%d", base, opts.Prerelease, n+1)
+	default:
+		bump := opts.Bump
+		if bump == BumpAuto {
+			// like ResolveBump does without commits
+			bump = BumpPatch
+		}
+
+		if pre != "" && bumpWithinPrerelease(bump, minor, patch) {
+			// the prerelease precedes base, thus base is the next version
+			next = base
+		} else if next, err = BumpVersion(base, bump); err != nil {
+			return "", err
+		}
+		if opts.Prerelease != "" {
+			next += "-" + opts.Prerelease + ".1"
+		}
+	}
+
+	if semver.Compare(next, ensureV(version)) <= 0 {
+		return "", fmt.Errorf("next version %s is not greater than %s", prefix+next, tag)
+	}
+
+	if opts.Build != "" {
+		next += "+" + opts.Build
+	}
+
+	return prefix + next, nil
+}
+
+// bumpWithinPrerelease returns whether incrementing the version with
+// components minor and patch using bump results in the same version when it
+// is a prerelease. For example, bumping MINOR of v1.4.0-rc.1 results in
+// v1.4.0, while bumping MINOR of v1.4.1-rc.1 results in v1.5.0.
+func bumpWithinPrerelease(bump Bump, minor, patch int) bool {
+
+	switch bump {
+	case BumpMajor:
+		return minor == 0 && patch == 0
+	case BumpMinor:
+		return patch == 0
+	default:
+		return true
+	}
+}
+
+// splitPrerelease splits a prerelease like "rc.2" into its identifier "rc"
+// and number 2. When the prerelease does not end with a number, the number
+// is 0 and the identifier is the complete prerelease.
+func splitPrerelease(pre string) (string, int) {
+
+	if i := strings.LastIndexByte(pre, '.'); i != -1 {
+		if n, err := strconv.Atoi(pre[i+1:]); err == nil {
+			return pre[:i], n
+		}
+	}
+	return pre, 0
+}
+
+func ensureV(tag string) string {
+
+	if !strings.HasPrefix(tag, "v") {
+		return "v" + tag
+	}
+	return tag
+}
+
+// parseVersion returns the numeric components of the semantic version tag.
+// Module prefix (for example "xgrpc/"), prerelease, and build metadata
+// are ignored.
+func parseVersion(tag string) (major, minor, patch int, err error) {
+
+	// ensure tag has the leading 'v' required by x/mod/semver
+	_, tag = splitTag(tag)
+	tag = ensureV(tag)
+
+	if !semver.IsValid(tag) {
+		return 0, 0, 0, fmt.Errorf("invalid semantic version: %q", tag)
+	}
+
+	// normalize to canonical form
+	ver := semver.Canonical(tag)
+
+	// strip prerelease and build metadata, operate on base version only
+	if pre := semver.Prerelease(ver); pre != "" {
+		ver = strings.TrimSuffix(ver, pre)
+	}
+	if build := semver.Build(ver); build != "" {
+		ver = strings.TrimSuffix(ver, build)
+	}
+
+	// extract numeric parts
+	core := strings.TrimPrefix(ver, "v")
+	parts := strings.Split(core, ".")
+	// be tolerant if tag is missing components (though module tags should not)
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid major in %q: %w", tag, err)
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid minor in %q: %w", tag, err)
+	}
+	if patch, err = strconv.Atoi(parts[2]); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid patch in %q: %w", tag, err)
+	}
+
+	return major, minor, patch, nil
+}
+
+// NextTag returns the next semantic version tag based on the latest tag found
+// on the given branch of repo using opts (see NextVersionWith). With BumpAuto, the
+// commits since the latest tag determine which component is incremented
+// (see ResolveBump).
+func NextTag(repo Repository, tagBranch string, opts VersionOptions) (string, error) {
+	latestTag, err := repo.LatestTag(tagBranch, "")
+	if err != nil {
+		return "", fmt.Errorf("latest tag: %w", err)
+	}
+
+	if opts.Bump == BumpAuto {
+		commits, err := repo.Log(latestTag, "HEAD")
+		if err != nil {
+			return "", fmt.Errorf("commits since %s: %w", latestTag, err)
+		}
+		opts.Bump = ResolveBump(latestTag, commits)
+	}
+
+	nextTag, err := NextVersionWith(latestTag, opts)
+	if err != nil {
+		return "", fmt.Errorf("next version: %w", err)
+	}
+	return nextTag, nil
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package git
+
+import (
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestParseBump(t *testing.T) {
+	for _, b := range []Bump{BumpAuto, BumpMajor, BumpMinor, BumpPatch} {
+		t.Run(b.String(), func(t *testing.T) {
+			have, err := ParseBump(b.String())
+			xt.OK(t, err)
+			xt.Eq(t, b, have)
+		})
+	}
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		have, err := ParseBump("Major")
+		xt.OK(t, err)
+		xt.Eq(t, BumpMajor, have)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseBump("huge")
+		xt.KO(t, err)
+		xt.Eq(t, `invalid bump "huge" (must be auto, major, minor, or patch)`, err.Error())
+	})
+}
+
+func TestResolveBump(t *testing.T) {
+	cases := map[string]struct {
+		tag      string
+		messages []string
+		exp      Bump
+	}{
+		"no commits":       {tag: "v1.2.3", exp: BumpPatch},
+		"fixes only":       {tag: "v1.2.3", messages: []string{"fix: a", "perf: b"}, exp: BumpPatch},
+		"not conventional": {tag: "v1.2.3", messages: []string{"Update README"}, exp: BumpPatch},
+		"feature":          {tag: "v1.2.3", messages: []string{"fix: a", "feat(x): b"}, exp: BumpMinor},
+		"breaking marker":  {tag: "v1.2.3", messages: []string{"feat: a", "fix!: b"}, exp: BumpMajor},
+		"breaking footer": {
+			tag:      "v1.2.3",
+			messages: []string{"refactor: a\n\nBREAKING CHANGE: b"},
+			exp:      BumpMajor,
+		},
+		"breaking initial development": {tag: "v0.4.1", messages: []string{"feat!: a"}, exp: BumpMinor},
+		"feature initial development":  {tag: "v0.4.1", messages: []string{"feat: a"}, exp: BumpMinor},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			xt.Eq(t, cs.exp, ResolveBump(cs.tag, commitsFromMessages(cs.messages...)))
+		})
+	}
+}
+
+func TestBumpVersion(t *testing.T) {
+	cases := map[string]struct {
+		tag  string
+		bump Bump
+		exp  string
+	}{
+		"major":              {tag: "v1.2.3", bump: BumpMajor, exp: "v2.0.0"},
+		"minor":              {tag: "v1.2.3", bump: BumpMinor, exp: "v1.3.0"},
+		"patch":              {tag: "v1.2.3", bump: BumpPatch, exp: "v1.2.4"},
+		"without v":          {tag: "1.2.3", bump: BumpPatch, exp: "v1.2.4"},
+		"missing components": {tag: "v1.6", bump: BumpPatch, exp: "v1.6.1"},
+		"module":             {tag: "xgrpc/v1.2.3", bump: BumpMinor, exp: "xgrpc/v1.3.0"},
+		"nested module":      {tag: "tools/xgen/v0.1.0", bump: BumpMajor, exp: "tools/xgen/v1.0.0"},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			have, err := BumpVersion(cs.tag, cs.bump)
+			xt.OK(t, err)
+			xt.Eq(t, cs.exp, have)
+		})
+	}
+
+	t.Run("auto not accepted", func(t *testing.T) {
+		_, err := BumpVersion("v1.2.3", BumpAuto)
+		xt.KO(t, err)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := BumpVersion("version1", BumpMinor)
+		xt.KO(t, err)
+		xt.Eq(t, `invalid semantic version: "version1"`, err.Error())
+	})
+}
+
+func TestNextVersion(t *testing.T) {
+	have, err := NextVersion("v1.2.3", false)
+	xt.OK(t, err)
+	xt.Eq(t, "v1.3.0", have)
+
+	have, err = NextVersion("v1.2.3", true)
+	xt.OK(t, err)
+	xt.Eq(t, "v1.2.4", have)
+}
+
+func TestNextVersionWith(t *testing.T) {
+	cases := map[string]struct {
+		tag  string
+		opts VersionOptions
+		exp  string
+	}{
+		"next prerelease":           {tag: "v1.4.0-rc.1", opts: VersionOptions{Prerelease: "rc"}, exp: "v1.4.0-rc.2"},
+		"prerelease without number": {tag: "v1.4.0-rc", opts: VersionOptions{Prerelease: "rc"}, exp: "v1.4.0-rc.1"},
+		"other prerelease":          {tag: "v1.4.0-beta.3", opts: VersionOptions{Prerelease: "rc"}, exp: "v1.4.0-rc.1"},
+		"start prerelease": {
+			tag:  "v1.3.2",
+			opts: VersionOptions{Bump: BumpMinor, Prerelease: "rc"},
+			exp:  "v1.4.0-rc.1",
+		},
+		"promote":                  {tag: "v1.4.0-rc.2", opts: VersionOptions{Promote: true}, exp: "v1.4.0"},
+		"promote with build":       {tag: "v1.4.0-rc.2+b1", opts: VersionOptions{Promote: true, Build: "b2"}, exp: "v1.4.0+b2"},
+		"bump patch of prerelease": {tag: "v1.4.0-rc.2", opts: VersionOptions{Bump: BumpPatch}, exp: "v1.4.0"},
+		"bump minor of prerelease": {tag: "v1.4.0-rc.2", opts: VersionOptions{Bump: BumpMinor}, exp: "v1.4.0"},
+		"bump major of prerelease": {tag: "v1.4.0-rc.2", opts: VersionOptions{Bump: BumpMajor}, exp: "v2.0.0"},
+		"bump major of major prerelease": {
+			tag:  "v2.0.0-beta.1",
+			opts: VersionOptions{Bump: BumpMajor},
+			exp:  "v2.0.0",
+		},
+		"bump minor of patch prerelease": {
+			tag:  "v1.4.1-rc.1",
+			opts: VersionOptions{Bump: BumpMinor},
+			exp:  "v1.5.0",
+		},
+		"bump prerelease with build": {
+			tag:  "v1.4.0-rc.2",
+			opts: VersionOptions{Bump: BumpMinor, Build: "b1"},
+			exp:  "v1.4.0+b1",
+		},
+		"bump with build": {
+			tag:  "v1.4.0",
+			opts: VersionOptions{Bump: BumpPatch, Build: "build.42"},
+			exp:  "v1.4.1+build.42",
+		},
+		"module prerelease": {
+			tag:  "xgrpc/v1.4.0-rc.1",
+			opts: VersionOptions{Prerelease: "rc"},
+			exp:  "xgrpc/v1.4.0-rc.2",
+		},
+		"module promote": {tag: "xgrpc/v1.4.0-rc.2", opts: VersionOptions{Promote: true}, exp: "xgrpc/v1.4.0"},
+		"default bump":   {tag: "v1.4.0", opts: VersionOptions{}, exp: "v1.5.0"},
+		"auto bump":      {tag: "v1.4.0", opts: VersionOptions{Bump: BumpAuto}, exp: "v1.4.1"},
+		"auto bump starting prerelease": {
+			tag:  "v1.4.0",
+			opts: VersionOptions{Bump: BumpAuto, Prerelease: "rc"},
+			exp:  "v1.4.1-rc.1",
+		},
+	}
+
+	for name, cs := range cases {
+		t.Run(name, func(t *testing.T) {
+			have, err := NextVersionWith(cs.tag, cs.opts)
+			xt.OK(t, err)
+			xt.Eq(t, cs.exp, have)
+		})
+	}
+
+	errCases := map[string]struct {
+		tag  string
+		opts VersionOptions
+		exp  string
+	}{
+		"promote final": {
+			tag:  "v1.4.0",
+			opts: VersionOptions{Promote: true},
+			exp:  `cannot promote "v1.4.0"; not a prerelease`,
+		},
+		"promote and prerelease": {
+			tag:  "v1.4.0-rc.1",
+			opts: VersionOptions{Promote: true, Prerelease: "rc"},
+			exp:  "cannot promote and start prerelease at the same time",
+		},
+		"lower prerelease": {
+			tag:  "v1.4.0-rc.1",
+			opts: VersionOptions{Prerelease: "beta"},
+			exp:  "next version v1.4.0-beta.1 is not greater than v1.4.0-rc.1",
+		},
+		"lower module prerelease": {
+			tag:  "xgrpc/v1.4.0-rc.1",
+			opts: VersionOptions{Prerelease: "beta"},
+			exp:  "next version xgrpc/v1.4.0-beta.1 is not greater than xgrpc/v1.4.0-rc.1",
+		},
+		"invalid prerelease": {
+			tag:  "v1.4.0",
+			opts: VersionOptions{Bump: BumpMinor, Prerelease: "rc_1"},
+			exp:  `invalid prerelease identifier "rc_1"`,
+		},
+		"invalid build": {
+			tag:  "v1.4.0",
+			opts: VersionOptions{Bump: BumpMinor, Build: "a+b"},
+			exp:  `invalid build metadata "a+b"`,
+		},
+	}
+
+	for name, cs := range errCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NextVersionWith(cs.tag, cs.opts)
+			xt.KO(t, err)
+			xt.Eq(t, cs.exp, err.Error())
+		})
+	}
+}
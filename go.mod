@@ -3,9 +3,11 @@ module github.com/golistic/xgo
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	golang.org/x/mod v0.30.0
 	google.golang.org/grpc v1.70.0
 	google.golang.org/protobuf v1.36.5
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
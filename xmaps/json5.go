@@ -0,0 +1,410 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// UnmarshalJSON5 decodes the JSON5 object data, keeping the order of its
+// members. JSON5 extends JSON with, for example, comments, unquoted keys,
+// single-quoted strings, and trailing commas (see https://json5.org).
+// Otherwise, it works like UnmarshalJSON, including decoding nested
+// objects as *OrderedMap[string, any] when V is any.
+//
+// Since JSON is valid JSON5, MarshalJSON is used for encoding. Infinity
+// and NaN cannot be decoded.
+func (om *OrderedMap[K, V]) UnmarshalJSON5(data []byte) error {
+
+	jsonData, err := json5ToJSON(data)
+	if err != nil {
+		return err
+	}
+
+	return om.UnmarshalJSON(jsonData)
+}
+
+// json5ToJSON translates the JSON5 document data to JSON.
+func json5ToJSON(data []byte) ([]byte, error) {
+
+	t := &json5Translator{data: data}
+
+	if err := t.value(); err != nil {
+		return nil, err
+	}
+
+	if err := t.skipSpace(); err != nil {
+		return nil, err
+	}
+	if t.pos < len(t.data) {
+		return nil, t.errorf("unexpected %q after top-level value", t.data[t.pos])
+	}
+
+	return t.out.Bytes(), nil
+}
+
+type json5Translator struct {
+	data []byte
+	pos  int
+	out  bytes.Buffer
+}
+
+func (t *json5Translator) errorf(format string, a ...any) error {
+
+	return fmt.Errorf("json5: offset %d: %s", t.pos, fmt.Sprintf(format, a...))
+}
+
+func (t *json5Translator) peek() (rune, int) {
+
+	if t.pos >= len(t.data) {
+		return -1, 0
+	}
+
+	return utf8.DecodeRune(t.data[t.pos:])
+}
+
+// skipSpace skips white space and comments.
+func (t *json5Translator) skipSpace() error {
+
+	for t.pos < len(t.data) {
+		r, size := t.peek()
+		switch {
+		case unicode.IsSpace(r) || r == '\ufeff':
+			t.pos += size
+		case bytes.HasPrefix(t.data[t.pos:], []byte("//")):
+			end := bytes.IndexAny(t.data[t.pos:], "\n\r\u2028\u2029")
+			if end == -1 {
+				t.pos = len(t.data)
+			} else {
+				t.pos += end
+			}
+		case bytes.HasPrefix(t.data[t.pos:], []byte("/*")):
+			end := bytes.Index(t.data[t.pos+2:], []byte("*/"))
+			if end == -1 {
+				return t.errorf("unterminated comment")
+			}
+			t.pos += end + 4
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (t *json5Translator) value() error {
+
+	if err := t.skipSpace(); err != nil {
+		return err
+	}
+
+	r, _ := t.peek()
+	switch {
+	case r == -1:
+		return t.errorf("unexpected end of input")
+	case r == '{':
+		return t.object()
+	case r == '[':
+		return t.array()
+	case r == '"' || r == '\'':
+		return t.string()
+	case r == '+' || r == '-' || r == '.' || (r >= '0' && r <= '9'):
+		return t.number()
+	}
+
+	switch name := t.identifier(); name {
+	case "true", "false", "null":
+		t.out.WriteString(name)
+		return nil
+	case "Infinity", "NaN":
+		return t.errorf("%s is not supported", name)
+	case "":
+		return t.errorf("invalid character %q", r)
+	default:
+		return t.errorf("invalid value %q", name)
+	}
+}
+
+func (t *json5Translator) object() error {
+
+	t.pos++ // opening brace
+	t.out.WriteByte('{')
+
+	for first := true; ; first = false {
+		if err := t.skipSpace(); err != nil {
+			return err
+		}
+
+		r, _ := t.peek()
+		if r == '}' {
+			break
+		}
+		if !first {
+			t.out.WriteByte(',')
+		}
+
+		switch {
+		case r == '"' || r == '\'':
+			if err := t.string(); err != nil {
+				return err
+			}
+		default:
+			name := t.identifier()
+			if name == "" {
+				return t.errorf("invalid object key")
+			}
+			t.out.WriteString(strconv.Quote(name))
+		}
+
+		if err := t.skipSpace(); err != nil {
+			return err
+		}
+		if r, _ := t.peek(); r != ':' {
+			return t.errorf("expected ':' after object key")
+		}
+		t.pos++
+		t.out.WriteByte(':')
+
+		if err := t.value(); err != nil {
+			return err
+		}
+
+		if err := t.skipSpace(); err != nil {
+			return err
+		}
+		r, _ = t.peek()
+		if r == ',' {
+			t.pos++
+			continue
+		}
+		if r != '}' {
+			return t.errorf("expected ',' or '}' in object")
+		}
+	}
+
+	t.pos++ // closing brace
+	t.out.WriteByte('}')
+
+	return nil
+}
+
+func (t *json5Translator) array() error {
+
+	t.pos++ // opening bracket
+	t.out.WriteByte('[')
+
+	for first := true; ; first = false {
+		if err := t.skipSpace(); err != nil {
+			return err
+		}
+
+		r, _ := t.peek()
+		if r == ']' {
+			break
+		}
+		if !first {
+			t.out.WriteByte(',')
+		}
+
+		if err := t.value(); err != nil {
+			return err
+		}
+
+		if err := t.skipSpace(); err != nil {
+			return err
+		}
+		r, _ = t.peek()
+		if r == ',' {
+			t.pos++
+			continue
+		}
+		if r != ']' {
+			return t.errorf("expected ',' or ']' in array")
+		}
+	}
+
+	t.pos++ // closing bracket
+	t.out.WriteByte(']')
+
+	return nil
+}
+
+// identifier reads an ECMAScript identifier name, as used for unquoted
+// keys, and for literals like true and null. Unicode escapes are not
+// supported.
+func (t *json5Translator) identifier() string {
+
+	start := t.pos
+	for {
+		r, size := t.peek()
+		if r == '$' || r == '_' || unicode.IsLetter(r) ||
+			(t.pos > start && (unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Pc) ||
+				r == '\u200c' || r == '\u200d')) {
+			t.pos += size
+			continue
+		}
+		break
+	}
+
+	return string(t.data[start:t.pos])
+}
+
+// string translates a single- or double-quoted string.
+func (t *json5Translator) string() error {
+
+	quote, _ := t.peek()
+	t.pos++
+
+	t.out.WriteByte('"')
+
+	for {
+		r, size := t.peek()
+		switch {
+		case r == -1:
+			return t.errorf("unterminated string")
+		case r == quote:
+			t.pos++
+			t.out.WriteByte('"')
+			return nil
+		case r == '"':
+			t.out.WriteString(`\"`)
+		case r == '\n' || r == '\r':
+			return t.errorf("unescaped line terminator in string")
+		case r < 0x20:
+			_, _ = fmt.Fprintf(&t.out, `\u%04x`, r)
+		case r == '\\':
+			t.pos++
+			if err := t.escape(); err != nil {
+				return err
+			}
+			continue
+		default:
+			t.out.Write(t.data[t.pos : t.pos+size])
+		}
+		t.pos += size
+	}
+}
+
+// escape translates the escape sequence following a backslash.
+func (t *json5Translator) escape() error {
+
+	r, size := t.peek()
+	t.pos += size
+
+	switch r {
+	case -1:
+		return t.errorf("unterminated string")
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		t.out.WriteByte('\\')
+		t.out.WriteRune(r)
+	case 'u':
+		if t.pos+4 > len(t.data) {
+			return t.errorf("invalid unicode escape")
+		}
+		if _, err := strconv.ParseUint(string(t.data[t.pos:t.pos+4]), 16, 16); err != nil {
+			return t.errorf("invalid unicode escape")
+		}
+		t.out.WriteString(`\u`)
+		t.out.Write(t.data[t.pos : t.pos+4])
+		t.pos += 4
+	case 'x':
+		if t.pos+2 > len(t.data) {
+			return t.errorf("invalid hexadecimal escape")
+		}
+		if _, err := strconv.ParseUint(string(t.data[t.pos:t.pos+2]), 16, 8); err != nil {
+			return t.errorf("invalid hexadecimal escape")
+		}
+		t.out.WriteString(`\u00`)
+		t.out.Write(t.data[t.pos : t.pos+2])
+		t.pos += 2
+	case 'v':
+		t.out.WriteString(`\u000b`)
+	case '0':
+		if r, _ := t.peek(); r >= '0' && r <= '9' {
+			return t.errorf("octal escapes are not supported")
+		}
+		t.out.WriteString(`\u0000`)
+	case '\r':
+		if r, _ := t.peek(); r == '\n' {
+			t.pos++
+		}
+	case '\n', '\u2028', '\u2029':
+		// line continuation
+	default:
+		if r >= '1' && r <= '9' {
+			return t.errorf("invalid escape '\\%c'", r)
+		}
+		if r < 0x20 {
+			_, _ = fmt.Fprintf(&t.out, `\u%04x`, r)
+			break
+		}
+		t.out.WriteRune(r)
+	}
+
+	return nil
+}
+
+// number translates a number, which can have a leading plus sign, be
+// hexadecimal, or have a leading or trailing decimal point.
+func (t *json5Translator) number() error {
+
+	r, _ := t.peek()
+	if r == '+' || r == '-' {
+		if r == '-' {
+			t.out.WriteByte('-')
+		}
+		t.pos++
+	}
+
+	rest := t.data[t.pos:]
+	if bytes.HasPrefix(rest, []byte("0x")) || bytes.HasPrefix(rest, []byte("0X")) {
+		t.pos += 2
+		start := t.pos
+		for t.pos < len(t.data) && isHexDigit(t.data[t.pos]) {
+			t.pos++
+		}
+		n, err := strconv.ParseUint(string(t.data[start:t.pos]), 16, 64)
+		if err != nil {
+			return t.errorf("invalid hexadecimal number")
+		}
+		t.out.WriteString(strconv.FormatUint(n, 10))
+		return nil
+	}
+
+	if name := t.identifier(); name != "" {
+		return t.errorf("%s is not supported", name)
+	}
+
+	start := t.pos
+	for t.pos < len(t.data) {
+		c := t.data[t.pos]
+		if (c >= '0' && c <= '9') || c == '.' || c == 'e' || c == 'E' ||
+			((c == '+' || c == '-') && (t.data[t.pos-1] == 'e' || t.data[t.pos-1] == 'E')) {
+			t.pos++
+			continue
+		}
+		break
+	}
+
+	mantissa, exponent := t.data[start:t.pos], []byte(nil)
+	if i := bytes.IndexAny(mantissa, "eE"); i != -1 {
+		mantissa, exponent = mantissa[:i], mantissa[i:]
+	}
+
+	if bytes.HasPrefix(mantissa, []byte(".")) {
+		t.out.WriteByte('0')
+	}
+	t.out.Write(bytes.TrimSuffix(mantissa, []byte(".")))
+	t.out.Write(exponent)
+
+	return nil
+}
+
+func isHexDigit(c byte) bool {
+
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestOrderedMap_UnmarshalJSON5(t *testing.T) {
+
+	t.Run("keeps order", func(t *testing.T) {
+		doc := `// configuration
+{
+  name: 'app',
+  "version": +3,
+  /* servers, by name */
+  servers: {
+    web: {port: 0x50, hosts: ['b', "a",],},
+    db: {port: 5432, ratio: .5, max: 10.,},
+  },
+}
+`
+		om := NewOrderedMap[string, any]()
+		xt.OK(t, om.UnmarshalJSON5([]byte(doc)))
+		xt.Eq(t, []string{"name", "version", "servers"}, om.Keys())
+
+		data, err := om.MarshalJSON()
+		xt.OK(t, err)
+		xt.Eq(t, `{"name":"app","version":3,"servers":{"web":{"port":80,"hosts":["b","a"]},`+
+			`"db":{"port":5432,"ratio":0.5,"max":10}}}`, string(data))
+	})
+
+	t.Run("strings", func(t *testing.T) {
+		cases := map[string]struct {
+			doc  string
+			want string
+		}{
+			"single quoted":     {doc: `{s: 'say "hi"'}`, want: `say "hi"`},
+			"escaped quote":     {doc: `{s: 'it\'s'}`, want: `it's`},
+			"hexadecimal":       {doc: `{s: '\x41B'}`, want: `AB`},
+			"line continuation": {doc: "{s: 'one \\\ntwo'}", want: `one two`},
+			"vertical tab":      {doc: `{s: '\v'}`, want: "\v"},
+			"null character":    {doc: `{s: '\0'}`, want: "\x00"},
+			"unicode key":       {doc: `{s: 'ok', ñ: 1}`, want: `ok`},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				om := NewOrderedMap[string, any]()
+				xt.OK(t, om.UnmarshalJSON5([]byte(c.doc)))
+				have, _ := om.Value("s")
+				xt.Eq(t, c.want, have)
+			})
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := map[string]string{
+			"infinity":             `{n: Infinity}`,
+			"not a number":         `{n: -NaN}`,
+			"unterminated string":  `{s: 'abc}`,
+			"unterminated comment": `{s: 1} /* `,
+			"missing colon":        `{s 1}`,
+			"trailing data":        `{s: 1} x`,
+			"octal escape":         `{s: '\01'}`,
+			"not an object":        `[1, 2]`,
+		}
+
+		for name, doc := range cases {
+			t.Run(name, func(t *testing.T) {
+				om := NewOrderedMap[string, any]()
+				xt.KO(t, om.UnmarshalJSON5([]byte(doc)))
+			})
+		}
+	})
+}
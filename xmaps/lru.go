@@ -0,0 +1,370 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"sync"
+	"time"
+)
+
+// LRUOrder defines which element an LRU evicts when it is full.
+type LRUOrder int
+
+const (
+	// AccessOrder evicts the least recently used element. Both reading
+	// and setting an element count as use.
+	AccessOrder LRUOrder = iota
+	// InsertionOrder evicts the element which was added first. Reading or
+	// updating an element does not change the order.
+	InsertionOrder
+)
+
+// EvictionReason is passed to LRUOptions.OnEvict, telling why an element
+// was removed.
+type EvictionReason int
+
+const (
+	// EvictedCapacity is used for elements removed to make room for
+	// a new element.
+	EvictedCapacity EvictionReason = iota + 1
+	// EvictedExpired is used for elements of which the TTL passed.
+	EvictedExpired
+)
+
+func (r EvictionReason) String() string {
+
+	switch r {
+	case EvictedCapacity:
+		return "capacity"
+	case EvictedExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// LRUOptions configures an LRU.
+type LRUOptions[K comparable, V any] struct {
+	// Capacity is the maximum number of elements. When zero, the number
+	// of elements is not limited, which is useful when only using TTL.
+	Capacity int
+	// Order defines which element is evicted first. Default is AccessOrder.
+	Order LRUOrder
+	// TTL is the time elements added using Set expire after. When zero,
+	// elements do not expire.
+	TTL time.Duration
+	// Now returns the current time; default is time.Now. It can be set,
+	// for example, to control time in tests.
+	Now func() time.Time
+	// OnEvict, when not nil, is called for each element evicted because of
+	// capacity or because it expired. It is not called for elements removed
+	// using Delete or Clear. OnEvict is called without holding the lock, so
+	// it can use the LRU.
+	OnEvict func(key K, value V, reason EvictionReason)
+}
+
+// LRUStats holds the statistics of an LRU.
+type LRUStats struct {
+	// Hits is the number of times Get found an element.
+	Hits uint64
+	// Misses is the number of times Get did not find an element,
+	// including elements which expired.
+	Misses uint64
+	// Evictions is the number of elements evicted because of capacity.
+	Evictions uint64
+	// Expirations is the number of elements removed because they expired.
+	Expirations uint64
+}
+
+// HitRatio returns the fraction of Get calls which found an element, or 0
+// when Get was not called.
+func (s LRUStats) HitRatio() float64 {
+
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+// LRU is a cache holding a limited number of elements, evicting the least
+// recently used, or the oldest, element when full. Elements can also expire
+// after a TTL. Expired elements are removed when accessed, when evicted
+// to make room, or using Purge.
+//
+// The zero value is an LRU without capacity and TTL, using time.Now; use
+// NewLRU to configure it.
+//
+// LRU is safe for concurrent use by multiple goroutines.
+type LRU[K comparable, V any] struct {
+	mu    sync.Mutex
+	om    OrderedMap[K, lruItem[V]]
+	opts  LRUOptions[K, V]
+	stats LRUStats
+}
+
+type lruItem[V any] struct {
+	value   V
+	expires time.Time // zero when not expiring
+}
+
+// lruEviction is an element evicted while holding the lock, reported
+// through OnEvict once released.
+type lruEviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// NewLRU returns a new LRU configured using opts. It panics when the
+// capacity or TTL is negative.
+func NewLRU[K comparable, V any](opts LRUOptions[K, V]) *LRU[K, V] {
+
+	if opts.Capacity < 0 {
+		panic("xmaps: negative LRU capacity")
+	}
+	if opts.TTL < 0 {
+		panic("xmaps: negative LRU TTL")
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &LRU[K, V]{opts: opts}
+}
+
+// Count returns the number of elements, including those which expired
+// but were not removed yet.
+func (c *LRU[K, V]) Count() int {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.om.Count()
+}
+
+// Capacity returns the maximum number of elements, or 0 when not limited.
+func (c *LRU[K, V]) Capacity() int {
+
+	return c.opts.Capacity
+}
+
+// Get returns the value stored for key and whether it was found. Using
+// AccessOrder, the element becomes the most recently used.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+
+	c.mu.Lock()
+
+	var evicted []lruEviction[K, V]
+	item, ok := c.om.Value(key)
+	if ok && c.expired(item) {
+		evicted = c.remove(key, item, EvictedExpired, evicted)
+		ok = false
+	}
+
+	if ok {
+		c.stats.Hits++
+		if c.opts.Order == AccessOrder {
+			c.om.MoveToBack(key)
+		}
+	} else {
+		c.stats.Misses++
+	}
+
+	c.mu.Unlock()
+	c.notify(evicted)
+
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return item.value, true
+}
+
+// Peek returns the value stored for key and whether it was found, without
+// changing the order or the statistics.
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.om.Value(key)
+	if !ok || c.expired(item) {
+		var zero V
+		return zero, false
+	}
+
+	return item.value, true
+}
+
+// Has returns whether key is stored and not expired, without changing
+// the order or the statistics.
+func (c *LRU[K, V]) Has(key K) bool {
+
+	_, ok := c.Peek(key)
+	return ok
+}
+
+// Set stores value for key, expiring after the TTL configured using
+// LRUOptions. When the LRU is full, elements are evicted.
+func (c *LRU[K, V]) Set(key K, value V) {
+
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL stores value for key expiring after ttl, or never when ttl is
+// zero or negative. When the LRU is full, elements are evicted following the
+// configured order; evicted elements which expired are reported as such.
+// Other expired elements are kept until accessed or removed using Purge.
+func (c *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+
+	c.mu.Lock()
+
+	item := lruItem[V]{value: value}
+	if ttl > 0 {
+		item.expires = c.now().Add(ttl)
+	}
+
+	exists := c.om.Has(key)
+	c.om.Set(key, item)
+	if exists && c.opts.Order == AccessOrder {
+		c.om.MoveToBack(key)
+	}
+
+	var evicted []lruEviction[K, V]
+	for c.opts.Capacity > 0 && c.om.Count() > c.opts.Capacity {
+		k, v, _ := c.om.First()
+		reason := EvictedCapacity
+		if c.expired(v) {
+			reason = EvictedExpired
+		}
+		evicted = c.remove(k, v, reason, evicted)
+	}
+
+	c.mu.Unlock()
+	c.notify(evicted)
+}
+
+// Delete removes the element with key, and returns whether it was stored.
+func (c *LRU[K, V]) Delete(key K) bool {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.om.Has(key) {
+		return false
+	}
+
+	c.om.Delete(key)
+	return true
+}
+
+// Purge removes all expired elements.
+func (c *LRU[K, V]) Purge() {
+
+	c.mu.Lock()
+	evicted := c.purge(nil)
+	c.mu.Unlock()
+
+	c.notify(evicted)
+}
+
+// Clear removes all elements. The statistics are kept.
+func (c *LRU[K, V]) Clear() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.om = OrderedMap[K, lruItem[V]]{}
+}
+
+// Keys returns the keys of the elements which did not expire, in the order
+// they would be evicted.
+func (c *LRU[K, V]) Keys() []K {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]K, 0, c.om.Count())
+	for k, item := range c.om.All() {
+		if !c.expired(item) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// Stats returns a copy of the statistics.
+func (c *LRU[K, V]) Stats() LRUStats {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// ResetStats sets all statistics to zero.
+func (c *LRU[K, V]) ResetStats() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = LRUStats{}
+}
+
+// now returns the current time using LRUOptions.Now, or time.Now when not
+// set, for example, for the zero value.
+func (c *LRU[K, V]) now() time.Time {
+
+	if c.opts.Now == nil {
+		return time.Now()
+	}
+	return c.opts.Now()
+}
+
+func (c *LRU[K, V]) expired(item lruItem[V]) bool {
+
+	return !item.expires.IsZero() && !c.now().Before(item.expires)
+}
+
+// remove removes the element with key, updating the statistics, and
+// appends it to evicted.
+func (c *LRU[K, V]) remove(key K, item lruItem[V], reason EvictionReason, evicted []lruEviction[K, V]) []lruEviction[K, V] {
+
+	c.om.Delete(key)
+
+	switch reason {
+	case EvictedCapacity:
+		c.stats.Evictions++
+	case EvictedExpired:
+		c.stats.Expirations++
+	}
+
+	return append(evicted, lruEviction[K, V]{key: key, value: item.value, reason: reason})
+}
+
+// purge removes all expired elements, and appends them to evicted.
+func (c *LRU[K, V]) purge(evicted []lruEviction[K, V]) []lruEviction[K, V] {
+
+	for k, item := range c.om.All() {
+		if c.expired(item) {
+			evicted = c.remove(k, item, EvictedExpired, evicted)
+		}
+	}
+
+	return evicted
+}
+
+// notify calls OnEvict for each element in evicted. It must be called
+// without holding the lock.
+func (c *LRU[K, V]) notify(evicted []lruEviction[K, V]) {
+
+	if c.opts.OnEvict == nil {
+		return
+	}
+
+	for _, e := range evicted {
+		c.opts.OnEvict(e.key, e.value, e.reason)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+// testClock is a clock for LRU tests which only moves when told to.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+
+	c.now = c.now.Add(d)
+}
+
+func TestLRU(t *testing.T) {
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		var evicted []string
+		c := NewLRU(LRUOptions[string, int]{
+			Capacity: 3,
+			OnEvict: func(key string, value int, reason EvictionReason) {
+				evicted = append(evicted, fmt.Sprintf("%s=%d (%s)", key, value, reason))
+			},
+		})
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		c.Set("c", 3)
+		_, _ = c.Get("a")
+		c.Set("d", 4)
+		c.Set("c", 30)
+		c.Set("e", 5)
+
+		xt.Eq(t, []string{"d", "c", "e"}, c.Keys())
+		xt.Eq(t, []string{"b=2 (capacity)", "a=1 (capacity)"}, evicted)
+	})
+
+	t.Run("evicts in insertion order", func(t *testing.T) {
+		c := NewLRU(LRUOptions[string, int]{Capacity: 2, Order: InsertionOrder})
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		_, _ = c.Get("a")
+		c.Set("a", 10)
+		c.Set("c", 3)
+
+		xt.Eq(t, []string{"b", "c"}, c.Keys())
+		_, ok := c.Get("a")
+		xt.Assert(t, !ok)
+	})
+
+	t.Run("peek and has do not change order", func(t *testing.T) {
+		c := NewLRU(LRUOptions[string, int]{Capacity: 2})
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		v, ok := c.Peek("a")
+		xt.Assert(t, ok)
+		xt.Eq(t, 1, v)
+		xt.Assert(t, c.Has("a"))
+		c.Set("c", 3)
+
+		xt.Eq(t, []string{"b", "c"}, c.Keys())
+		xt.Eq(t, LRUStats{Evictions: 1}, c.Stats())
+	})
+
+	t.Run("expires using TTL", func(t *testing.T) {
+		clock := &testClock{now: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)}
+		var evicted []string
+		c := NewLRU(LRUOptions[string, int]{
+			TTL: time.Minute,
+			Now: clock.Now,
+			OnEvict: func(key string, _ int, reason EvictionReason) {
+				evicted = append(evicted, key+" ("+reason.String()+")")
+			},
+		})
+
+		c.Set("a", 1)
+		c.SetWithTTL("b", 2, time.Hour)
+		c.SetWithTTL("forever", 3, 0)
+
+		clock.Advance(59 * time.Second)
+		_, ok := c.Get("a")
+		xt.Assert(t, ok)
+
+		clock.Advance(time.Second)
+		_, ok = c.Get("a")
+		xt.Assert(t, !ok)
+		xt.Assert(t, !c.Has("a"))
+		xt.Eq(t, []string{"b", "forever"}, c.Keys())
+
+		clock.Advance(time.Hour)
+		xt.Eq(t, []string{"forever"}, c.Keys())
+		xt.Eq(t, 2, c.Count())
+		c.Purge()
+		xt.Eq(t, 1, c.Count())
+
+		xt.Eq(t, []string{"a (expired)", "b (expired)"}, evicted)
+		xt.Eq(t, LRUStats{Hits: 1, Misses: 1, Expirations: 2}, c.Stats())
+	})
+
+	t.Run("evicted elements which expired", func(t *testing.T) {
+		clock := &testClock{now: time.Now()}
+		c := NewLRU(LRUOptions[string, int]{Capacity: 3, Now: clock.Now})
+
+		c.SetWithTTL("a", 1, time.Second)
+		c.Set("b", 2)
+		c.SetWithTTL("c", 3, time.Second)
+		clock.Advance(time.Second)
+		c.Set("d", 4)
+
+		// only the element to evict is checked; c is removed when purged
+		xt.Eq(t, []string{"b", "d"}, c.Keys())
+		xt.Eq(t, 3, c.Count())
+		xt.Eq(t, LRUStats{Expirations: 1}, c.Stats())
+	})
+
+	t.Run("statistics", func(t *testing.T) {
+		c := NewLRU(LRUOptions[string, int]{Capacity: 1})
+
+		xt.Eq(t, 0.0, c.Stats().HitRatio())
+
+		c.Set("a", 1)
+		_, _ = c.Get("a")
+		_, _ = c.Get("a")
+		_, _ = c.Get("a")
+		_, _ = c.Get("b")
+		c.Set("b", 2)
+
+		stats := c.Stats()
+		xt.Eq(t, LRUStats{Hits: 3, Misses: 1, Evictions: 1}, stats)
+		xt.Eq(t, 0.75, stats.HitRatio())
+
+		c.ResetStats()
+		xt.Eq(t, LRUStats{}, c.Stats())
+	})
+
+	t.Run("delete and clear do not call OnEvict", func(t *testing.T) {
+		c := NewLRU(LRUOptions[string, int]{
+			OnEvict: func(key string, _ int, _ EvictionReason) {
+				t.Errorf("unexpected eviction of %s", key)
+			},
+		})
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		xt.Assert(t, c.Delete("a"))
+		xt.Assert(t, !c.Delete("a"))
+		c.Clear()
+		xt.Eq(t, 0, c.Count())
+	})
+
+	t.Run("OnEvict can use the LRU", func(t *testing.T) {
+		var stored []bool
+		var c *LRU[string, int]
+		c = NewLRU(LRUOptions[string, int]{
+			Capacity: 1,
+			OnEvict: func(key string, _ int, _ EvictionReason) {
+				stored = append(stored, c.Has(key))
+			},
+		})
+
+		c.Set("a", 1)
+		c.Set("b", 2)
+		xt.Eq(t, []bool{false}, stored)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var c LRU[string, int]
+
+		c.SetWithTTL("a", 1, time.Hour)
+		c.Set("b", 2)
+		v, ok := c.Get("a")
+		xt.Assert(t, ok)
+		xt.Eq(t, 1, v)
+		xt.Eq(t, []string{"b", "a"}, c.Keys())
+	})
+
+	t.Run("negative capacity panics", func(t *testing.T) {
+		defer func() {
+			xt.Assert(t, recover() != nil)
+		}()
+		NewLRU(LRUOptions[string, int]{Capacity: -1})
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		c := NewLRU(LRUOptions[int, int]{Capacity: 10})
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 100 {
+					c.Set(i*100+j, j)
+					_, _ = c.Get(j)
+				}
+			}()
+		}
+		wg.Wait()
+
+		xt.Eq(t, 10, c.Count())
+		stats := c.Stats()
+		xt.Eq(t, uint64(800), stats.Hits+stats.Misses)
+		xt.Eq(t, uint64(790), stats.Evictions)
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// Functions working on plain Go maps have a counterpart, with the Ordered
+// suffix, working on OrderedMap. The latter keep the order of the elements.
+// Like nil maps, nil OrderedMaps are treated as empty.
+
+// orEmpty returns om, or an empty OrderedMap when om is nil.
+func orEmpty[K comparable, V any](om *OrderedMap[K, V]) *OrderedMap[K, V] {
+
+	if om == nil {
+		return NewOrderedMap[K, V]()
+	}
+	return om
+}
+
+// Merge returns a new map holding the elements of all maps. When a key is
+// found in more than one map, the value of the last map is used.
+func Merge[M ~map[K]V, K comparable, V any](ms ...M) M {
+
+	return MergeFunc(nil, ms...)
+}
+
+// MergeFunc returns a new map holding the elements of all maps. When a key
+// is found in more than one map, resolve is called with the key, the value
+// merged so far, and the value of the next map; it returns the value to use.
+// When resolve is nil, the value of the last map is used.
+func MergeFunc[M ~map[K]V, K comparable, V any](resolve func(key K, current, other V) V, ms ...M) M {
+
+	merged := M{}
+	for _, m := range ms {
+		for k, v := range m {
+			if current, ok := merged[k]; ok && resolve != nil {
+				v = resolve(k, current, v)
+			}
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// MergeOrdered returns a new OrderedMap holding the elements of all maps.
+// Keys are ordered by their first appearance. When a key is found in more
+// than one map, the value of the last map is used.
+func MergeOrdered[K comparable, V any](oms ...*OrderedMap[K, V]) *OrderedMap[K, V] {
+
+	return MergeOrderedFunc(nil, oms...)
+}
+
+// MergeOrderedFunc works like MergeFunc, but for OrderedMap. Keys are ordered
+// by their first appearance.
+func MergeOrderedFunc[K comparable, V any](resolve func(key K, current, other V) V, oms ...*OrderedMap[K, V]) *OrderedMap[K, V] {
+
+	merged := NewOrderedMap[K, V]()
+	merged.init()
+
+	for _, om := range oms {
+		for k, v := range orEmpty(om).All() {
+			if current, ok := merged.Value(k); ok && resolve != nil {
+				v = resolve(k, current, v)
+			}
+			merged.Set(k, v)
+		}
+	}
+
+	return merged
+}
+
+// Invert returns a new map with the keys and values of m swapped. When
+// values are not unique, which of their keys is used is not specified.
+func Invert[M ~map[K]V, K, V comparable](m M) map[V]K {
+
+	inverted := make(map[V]K, len(m))
+	for k, v := range m {
+		inverted[v] = k
+	}
+
+	return inverted
+}
+
+// InvertOrdered returns a new OrderedMap with the keys and values of om
+// swapped. When values are not unique, the last key is used, while the
+// order is that of the first.
+func InvertOrdered[K, V comparable](om *OrderedMap[K, V]) *OrderedMap[V, K] {
+
+	inverted := NewOrderedMap[V, K]()
+	inverted.init()
+
+	for k, v := range orEmpty(om).All() {
+		inverted.Set(v, k)
+	}
+
+	return inverted
+}
+
+// Filter returns a new map holding the elements of m for which keep
+// returns true.
+func Filter[M ~map[K]V, K comparable, V any](m M, keep func(key K, value V) bool) M {
+
+	filtered := M{}
+	for k, v := range m {
+		if keep(k, v) {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
+}
+
+// FilterOrdered returns a new OrderedMap holding the elements of om for
+// which keep returns true.
+func FilterOrdered[K comparable, V any](om *OrderedMap[K, V], keep func(key K, value V) bool) *OrderedMap[K, V] {
+
+	filtered := NewOrderedMap[K, V]()
+	filtered.init()
+
+	for k, v := range orEmpty(om).All() {
+		if keep(k, v) {
+			filtered.Set(k, v)
+		}
+	}
+
+	return filtered
+}
+
+// MapValues returns a new map with the same keys as m, and as values the
+// result of calling fn with each value of m.
+func MapValues[M ~map[K]V, K comparable, V, W any](m M, fn func(value V) W) map[K]W {
+
+	mapped := make(map[K]W, len(m))
+	for k, v := range m {
+		mapped[k] = fn(v)
+	}
+
+	return mapped
+}
+
+// MapValuesOrdered returns a new OrderedMap with the same keys as om, and as
+// values the result of calling fn with each value of om.
+func MapValuesOrdered[K comparable, V, W any](om *OrderedMap[K, V], fn func(value V) W) *OrderedMap[K, W] {
+
+	mapped := NewOrderedMap[K, W]()
+	mapped.init()
+
+	for k, v := range orEmpty(om).All() {
+		mapped.Set(k, fn(v))
+	}
+
+	return mapped
+}
+
+// MapKeys returns a new map with as keys the result of calling fn with each
+// key of m, and the same values. When fn returns the same key more than
+// once, which of the values is used is not specified.
+func MapKeys[M ~map[K]V, K, L comparable, V any](m M, fn func(key K) L) map[L]V {
+
+	mapped := make(map[L]V, len(m))
+	for k, v := range m {
+		mapped[fn(k)] = v
+	}
+
+	return mapped
+}
+
+// MapKeysOrdered returns a new OrderedMap with as keys the result of calling
+// fn with each key of om, and the same values. When fn returns the same key
+// more than once, the last value is used, while the order is that of the
+// first.
+func MapKeysOrdered[K, L comparable, V any](om *OrderedMap[K, V], fn func(key K) L) *OrderedMap[L, V] {
+
+	mapped := NewOrderedMap[L, V]()
+	mapped.init()
+
+	for k, v := range orEmpty(om).All() {
+		mapped.Set(fn(k), v)
+	}
+
+	return mapped
+}
+
+// GroupBy returns a map of the elements of s grouped by the key returned by
+// fn. The elements of each group keep their order.
+func GroupBy[S ~[]E, E any, K comparable](s S, fn func(elem E) K) map[K][]E {
+
+	groups := map[K][]E{}
+	for _, e := range s {
+		k := fn(e)
+		groups[k] = append(groups[k], e)
+	}
+
+	return groups
+}
+
+// GroupByOrdered works like GroupBy, but returns an OrderedMap with the
+// groups ordered by the first appearance of their key.
+func GroupByOrdered[S ~[]E, E any, K comparable](s S, fn func(elem E) K) *OrderedMap[K, []E] {
+
+	groups := NewOrderedMap[K, []E]()
+	groups.init()
+
+	for _, e := range s {
+		k := fn(e)
+		group, _ := groups.Value(k)
+		groups.Set(k, append(group, e))
+	}
+
+	return groups
+}
+
+// MapDiff holds the differences between two maps as returned by Diff.
+type MapDiff[K comparable] struct {
+	// Added are the keys only found in the second map.
+	Added []K
+	// Removed are the keys only found in the first map.
+	Removed []K
+	// Changed are the keys found in both maps with different values.
+	Changed []K
+}
+
+// IsZero returns whether there are no differences.
+func (d MapDiff[K]) IsZero() bool {
+
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff returns the keys added, removed, or changed when going from map a
+// to map b. The order of the keys is unspecified; use DiffOrdered when
+// it matters.
+func Diff[M ~map[K]V, K, V comparable](a, b M) MapDiff[K] {
+
+	return DiffFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// DiffFunc works like Diff, but compares values using eq. The order of
+// the keys is unspecified.
+func DiffFunc[M ~map[K]V, K comparable, V any](a, b M, eq func(x, y V) bool) MapDiff[K] {
+
+	var d MapDiff[K]
+
+	for k, va := range a {
+		vb, ok := b[k]
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, k)
+		case !eq(va, vb):
+			d.Changed = append(d.Changed, k)
+		}
+	}
+
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			d.Added = append(d.Added, k)
+		}
+	}
+
+	return d
+}
+
+// DiffOrdered works like Diff, but for OrderedMap. Added keys are in the
+// order of b, removed and changed keys in the order of a.
+func DiffOrdered[K, V comparable](a, b *OrderedMap[K, V]) MapDiff[K] {
+
+	return DiffOrderedFunc(a, b, func(x, y V) bool { return x == y })
+}
+
+// DiffOrderedFunc works like DiffOrdered, but compares values using eq.
+func DiffOrderedFunc[K comparable, V any](a, b *OrderedMap[K, V], eq func(x, y V) bool) MapDiff[K] {
+
+	var d MapDiff[K]
+	a, b = orEmpty(a), orEmpty(b)
+
+	for k, va := range a.All() {
+		vb, ok := b.Value(k)
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, k)
+		case !eq(va, vb):
+			d.Changed = append(d.Changed, k)
+		}
+	}
+
+	for k := range b.KeysSeq() {
+		if !a.Has(k) {
+			d.Added = append(d.Added, k)
+		}
+	}
+
+	return d
+}
+
+// SortedKeys returns the keys of m in ascending order.
+func SortedKeys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
+
+	return slices.Sorted(maps.Keys(m))
+}
+
+// SortedKeysOrdered returns the keys of om in ascending order, instead of
+// the order of om.
+func SortedKeysOrdered[K cmp.Ordered, V any](om *OrderedMap[K, V]) []K {
+
+	return slices.Sorted(orEmpty(om).KeysSeq())
+}
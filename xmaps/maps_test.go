@@ -0,0 +1,146 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestMerge(t *testing.T) {
+
+	t.Run("last wins", func(t *testing.T) {
+		have := Merge(map[string]int{"a": 1, "b": 2}, nil, map[string]int{"b": 20, "c": 3})
+		xt.Eq(t, map[string]int{"a": 1, "b": 20, "c": 3}, have)
+	})
+
+	t.Run("resolve conflicts", func(t *testing.T) {
+		sum := func(_ string, current, other int) int { return current + other }
+		have := MergeFunc(sum, map[string]int{"a": 1, "b": 2}, map[string]int{"b": 20}, map[string]int{"b": 200})
+		xt.Eq(t, map[string]int{"a": 1, "b": 222}, have)
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		have := MergeOrdered(newTestOrderedMap("b", "a"), nil, newTestOrderedMap("c", "b"))
+		keys, values := have.KeysValues()
+		xt.Eq(t, []string{"b", "a", "c"}, keys)
+		xt.Eq(t, []int{2, 2, 1}, values)
+	})
+
+	t.Run("ordered resolve conflicts", func(t *testing.T) {
+		first := func(_ string, current, _ int) int { return current }
+		have := MergeOrderedFunc(first, newTestOrderedMap("b", "a"), newTestOrderedMap("c", "b"))
+		xt.Eq(t, []int{1, 2, 1}, have.Values())
+	})
+}
+
+func TestInvert(t *testing.T) {
+
+	xt.Eq(t, map[int]string{1: "a", 2: "b"}, Invert(map[string]int{"a": 1, "b": 2}))
+
+	om := newTestOrderedMap("a", "b", "c")
+	om.Set("b", 1)
+	have := InvertOrdered(om)
+	xt.Eq(t, []int{1, 3}, have.Keys())
+	xt.Eq(t, []string{"b", "c"}, have.Values())
+}
+
+func TestFilter(t *testing.T) {
+
+	odd := func(_ string, v int) bool { return v%2 == 1 }
+
+	xt.Eq(t, map[string]int{"a": 1, "c": 3}, Filter(map[string]int{"a": 1, "b": 2, "c": 3}, odd))
+	xt.Eq(t, []string{"c", "a"}, FilterOrdered(newTestOrderedMap("c", "b", "a"), odd).Keys())
+}
+
+func TestMapValues(t *testing.T) {
+
+	double := func(v int) int { return v * 2 }
+
+	xt.Eq(t, map[string]int{"a": 2, "b": 4}, MapValues(map[string]int{"a": 1, "b": 2}, double))
+
+	have := MapValuesOrdered(newTestOrderedMap("b", "a"), double)
+	xt.Eq(t, []string{"b", "a"}, have.Keys())
+	xt.Eq(t, []int{2, 4}, have.Values())
+}
+
+func TestMapKeys(t *testing.T) {
+
+	xt.Eq(t, map[string]int{"A": 1, "B": 2}, MapKeys(map[string]int{"a": 1, "b": 2}, strings.ToUpper))
+
+	have := MapKeysOrdered(newTestOrderedMap("b", "a", "B"), strings.ToUpper)
+	xt.Eq(t, []string{"B", "A"}, have.Keys())
+	xt.Eq(t, []int{3, 2}, have.Values())
+}
+
+func TestGroupBy(t *testing.T) {
+
+	words := []string{"go", "rust", "c", "zig", "java", "d"}
+	length := func(s string) int { return len(s) }
+
+	xt.Eq(t, map[int][]string{1: {"c", "d"}, 2: {"go"}, 3: {"zig"}, 4: {"rust", "java"}}, GroupBy(words, length))
+
+	have := GroupByOrdered(words, length)
+	xt.Eq(t, []int{2, 4, 1, 3}, have.Keys())
+	xt.Eq(t, [][]string{{"go"}, {"rust", "java"}, {"c", "d"}, {"zig"}}, have.Values())
+}
+
+func TestDiff(t *testing.T) {
+
+	t.Run("plain maps", func(t *testing.T) {
+		d := Diff(map[string]int{"a": 1, "b": 2, "c": 3, "x": 9}, map[string]int{"a": 1, "b": 20, "d": 4, "y": 8})
+		slices.Sort(d.Added)
+		slices.Sort(d.Removed)
+		xt.Eq(t, MapDiff[string]{Added: []string{"d", "y"}, Removed: []string{"c", "x"}, Changed: []string{"b"}}, d)
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		d := Diff(map[string]int{"a": 1}, map[string]int{"a": 1})
+		xt.Assert(t, d.IsZero())
+	})
+
+	t.Run("compare using function", func(t *testing.T) {
+		d := DiffFunc(map[string][]int{"a": {1}, "b": {2}}, map[string][]int{"a": {1}, "b": {2, 2}}, slices.Equal)
+		xt.Eq(t, MapDiff[string]{Changed: []string{"b"}}, d)
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		a := newTestOrderedMap("z", "y", "x", "w")
+		b := newTestOrderedMap("y", "z", "v", "u")
+		b.Set("w", 4)
+
+		d := DiffOrdered(a, b)
+		xt.Eq(t, MapDiff[string]{Added: []string{"v", "u"}, Removed: []string{"x"}, Changed: []string{"z", "y"}}, d)
+
+		d = DiffOrderedFunc(a, b, func(x, y int) bool { return true })
+		xt.Eq(t, MapDiff[string]{Added: []string{"v", "u"}, Removed: []string{"x"}}, d)
+	})
+}
+
+func TestSortedKeys(t *testing.T) {
+
+	xt.Eq(t, []string{"a", "b", "c"}, SortedKeys(map[string]int{"c": 3, "a": 1, "b": 2}))
+	xt.Eq(t, []string{"a", "b", "c"}, SortedKeysOrdered(newTestOrderedMap("c", "a", "b")))
+}
+
+func TestOrdered_nil(t *testing.T) {
+
+	var om *OrderedMap[string, int]
+	keep := func(string, int) bool { return true }
+
+	xt.Eq(t, 0, MergeOrdered(om, nil).Count())
+	xt.Eq(t, 0, InvertOrdered(om).Count())
+	xt.Eq(t, 0, FilterOrdered(om, keep).Count())
+	xt.Eq(t, 0, MapValuesOrdered(om, strconv.Itoa).Count())
+	xt.Eq(t, 0, MapKeysOrdered(om, strings.ToUpper).Count())
+	xt.Eq(t, 0, len(SortedKeysOrdered(om)))
+
+	a := newTestOrderedMap("a", "b")
+	xt.Eq(t, MapDiff[string]{Added: []string{"a", "b"}}, DiffOrdered(om, a))
+	xt.Eq(t, MapDiff[string]{Removed: []string{"a", "b"}}, DiffOrdered(a, om))
+	xt.Assert(t, DiffOrdered(om, om).IsZero())
+}
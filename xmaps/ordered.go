@@ -1,15 +1,22 @@
-// Copyright (c) 2023, 2024, Geert JM Vanderkelen
+// Copyright (c) 2023, 2024, 2026, Geert JM Vanderkelen
 
 package xmaps
 
 import (
+	"bufio"
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
+	"reflect"
 	"slices"
-	"strings"
+	"strconv"
 )
 
+// ErrKeysMustBeStrings is returned when encoding or decoding JSON using
+// keys which cannot be represented as JSON object member names.
 var ErrKeysMustBeStrings = fmt.Errorf("keys must be strings")
 
 func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
@@ -24,161 +31,499 @@ func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
 // method to set a key with a particular value.
 // Use the Keys method to retrieves keys, Values to get the
 // values. To get both, which probably what you want, use
-// the KeysValues method.
+// the KeysValues method, or iterate using All.
+//
+// Elements are stored in a doubly linked list indexed by key, so
+// that Set, Has, Value, and Delete take constant time.
 type OrderedMap[K comparable, V any] struct {
-	pairs map[K]V
-	order []K
+	entries map[K]*entry[K, V]
+	first   *entry[K, V]
+	last    *entry[K, V]
+}
+
+// entry is an element of OrderedMap linked to the elements added
+// before (prev) and after (next).
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	prev  *entry[K, V]
+	next  *entry[K, V]
 }
 
 func (om *OrderedMap[K, V]) init() {
 
-	om.pairs = map[K]V{}
-	om.order = []K{}
+	om.entries = map[K]*entry[K, V]{}
 }
 
 // Count returns the number of elements in the map.
 func (om *OrderedMap[K, V]) Count() int {
-	return len(om.order)
+	return len(om.entries)
 }
 
 // Set key in OrderedMap to value. Previously stored values
 // are overwritten, but the order does not change.
 func (om *OrderedMap[K, V]) Set(key K, value V) {
 
-	if om.pairs == nil {
+	if om.entries == nil {
 		om.init()
 	}
 
-	om.pairs[key] = value
-	if !om.has(key) {
-		om.order = append(om.order, key)
+	if e, ok := om.entries[key]; ok {
+		e.value = value
+		return
 	}
+
+	e := &entry[K, V]{key: key, value: value}
+	om.insertAfter(e, om.last)
+	om.entries[key] = e
 }
 
 // Delete deletes the element with the specified key from
 // the OrderedMap.
 func (om *OrderedMap[K, V]) Delete(key K) {
 
-	om.order = slices.DeleteFunc(om.order, func(t K) bool {
-		return t == key
-	})
+	e, ok := om.entries[key]
+	if !ok {
+		return
+	}
 
-	delete(om.pairs, key)
+	om.unlink(e)
+	delete(om.entries, key)
 }
 
-// Keys returns keys as slice of string.
+// unlink removes e from the linked list.
+func (om *OrderedMap[K, V]) unlink(e *entry[K, V]) {
+
+	if e.prev == nil {
+		om.first = e.next
+	} else {
+		e.prev.next = e.next
+	}
+
+	if e.next == nil {
+		om.last = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+
+	e.prev, e.next = nil, nil
+}
+
+// Keys returns a copy of the keys in order.
 func (om *OrderedMap[K, V]) Keys() []K {
 
-	return om.order
+	res := make([]K, 0, len(om.entries))
+	for e := om.first; e != nil; e = e.next {
+		res = append(res, e.key)
+	}
+
+	return res
 }
 
 func (om *OrderedMap[K, V]) values() []V {
 
-	res := make([]V, len(om.order))
-	for i, k := range om.order {
-		res[i] = om.pairs[k]
+	res := make([]V, 0, len(om.entries))
+	for e := om.first; e != nil; e = e.next {
+		res = append(res, e.value)
 	}
 
 	return res
 }
 
-// Values returns the values as slice of interfaces.
+// Values returns a copy of the values in order.
 func (om *OrderedMap[K, V]) Values() []V {
 
 	return om.values()
 }
 
-// KeysValues returns the keys as slice of strings, and values as slice of interfaces.
+// KeysValues returns a copy of the keys and of the values in order.
 func (om *OrderedMap[K, V]) KeysValues() ([]K, []V) {
 
-	return om.order, om.values()
+	return om.Keys(), om.values()
 }
 
 // Has returns whether the map contains key.
 func (om *OrderedMap[K, V]) Has(key K) bool {
 
-	return om.has(key)
+	_, ok := om.entries[key]
+	return ok
+}
+
+// Value returns the value for key and also whether it was found.
+// The bool is returned because value could be nil.
+func (om *OrderedMap[K, V]) Value(key K) (V, bool) {
+
+	if e, ok := om.entries[key]; ok {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// All returns an iterator over the key-value pairs in order they were
+// added. Deleting the current element while iterating is allowed.
+func (om *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+
+	return func(yield func(K, V) bool) {
+		for e := om.first; e != nil; {
+			next := e.next
+			if !yield(e.key, e.value) {
+				return
+			}
+			e = next
+		}
+	}
 }
 
-func (om *OrderedMap[K, V]) has(key K) bool {
+// Backward returns an iterator over the key-value pairs in reverse order,
+// starting with the last added. Deleting the current element while
+// iterating is allowed.
+func (om *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+
+	return func(yield func(K, V) bool) {
+		for e := om.last; e != nil; {
+			prev := e.prev
+			if !yield(e.key, e.value) {
+				return
+			}
+			e = prev
+		}
+	}
+}
+
+// KeysSeq returns an iterator over the keys in order.
+func (om *OrderedMap[K, V]) KeysSeq() iter.Seq[K] {
 
-	for _, e := range om.order {
-		if e == key {
-			return true
+	return func(yield func(K) bool) {
+		for k := range om.All() {
+			if !yield(k) {
+				return
+			}
 		}
 	}
+}
 
-	return false
+// ValuesSeq returns an iterator over the values in order.
+func (om *OrderedMap[K, V]) ValuesSeq() iter.Seq[V] {
+
+	return func(yield func(V) bool) {
+		for _, v := range om.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
 }
 
-// Value returns the value for key and also whether it was found.
-// The bool is returned because value could be nil.
-func (om *OrderedMap[K, V]) Value(key K) (V, bool) {
+// At returns the key and value of the element at position i, and whether
+// i is within range. Since elements are linked, this takes linear time.
+func (om *OrderedMap[K, V]) At(i int) (K, V, bool) {
+
+	e := om.at(i)
+	if e == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+
+	return e.key, e.value, true
+}
+
+// at returns the element at position i, or nil when out of range. The
+// list is walked from the end closest to i.
+func (om *OrderedMap[K, V]) at(i int) *entry[K, V] {
+
+	n := len(om.entries)
+	if i < 0 || i >= n {
+		return nil
+	}
+
+	if i < n/2 {
+		e := om.first
+		for ; i > 0; i-- {
+			e = e.next
+		}
+		return e
+	}
+
+	e := om.last
+	for i = n - 1 - i; i > 0; i-- {
+		e = e.prev
+	}
+	return e
+}
+
+// IndexOf returns the position of key, or -1 when the map does not
+// contain key. Since elements are linked, this takes linear time.
+func (om *OrderedMap[K, V]) IndexOf(key K) int {
+
+	if !om.Has(key) {
+		return -1
+	}
+
+	i := 0
+	for e := om.first; e.key != key; e = e.next {
+		i++
+	}
+
+	return i
+}
+
+// First returns the key and value of the first element, and whether
+// the map has elements.
+func (om *OrderedMap[K, V]) First() (K, V, bool) {
+
+	return om.At(0)
+}
+
+// Last returns the key and value of the last element, and whether
+// the map has elements.
+func (om *OrderedMap[K, V]) Last() (K, V, bool) {
+
+	return om.At(len(om.entries) - 1)
+}
+
+// MoveToFront moves the element with key to the front, and returns
+// whether the map contains key.
+func (om *OrderedMap[K, V]) MoveToFront(key K) bool {
+
+	e, ok := om.entries[key]
+	if !ok {
+		return false
+	}
+
+	if e != om.first {
+		om.unlink(e)
+		om.insertBefore(e, om.first)
+	}
+
+	return true
+}
+
+// MoveToBack moves the element with key to the back, as if it was added
+// last, and returns whether the map contains key.
+func (om *OrderedMap[K, V]) MoveToBack(key K) bool {
+
+	e, ok := om.entries[key]
+	if !ok {
+		return false
+	}
+
+	if e != om.last {
+		om.unlink(e)
+		om.insertAfter(e, om.last)
+	}
 
-	return om.pairs[key], om.has(key)
+	return true
 }
 
+// MoveAfter moves the element with key right after the element with
+// mark, and returns whether the map contains both key and mark. Nothing
+// changes when key and mark are equal.
+func (om *OrderedMap[K, V]) MoveAfter(key, mark K) bool {
+
+	e, ok := om.entries[key]
+	if !ok {
+		return false
+	}
+
+	m, ok := om.entries[mark]
+	if !ok {
+		return false
+	}
+
+	if e != m && m.next != e {
+		om.unlink(e)
+		om.insertAfter(e, m)
+	}
+
+	return true
+}
+
+// SortFunc sorts the elements by key using cmp like slices.SortStableFunc.
+// To sort by value, cmp can retrieve the values using Value.
+func (om *OrderedMap[K, V]) SortFunc(cmp func(a, b K) int) {
+
+	elements := make([]*entry[K, V], 0, len(om.entries))
+	for e := om.first; e != nil; e = e.next {
+		elements = append(elements, e)
+	}
+
+	slices.SortStableFunc(elements, func(a, b *entry[K, V]) int {
+		return cmp(a.key, b.key)
+	})
+
+	om.first, om.last = nil, nil
+	for _, e := range elements {
+		e.prev, e.next = nil, nil
+		om.insertAfter(e, om.last)
+	}
+}
+
+// insertBefore links e before mark; when mark is nil, e becomes
+// the only element.
+func (om *OrderedMap[K, V]) insertBefore(e, mark *entry[K, V]) {
+
+	if mark == nil {
+		om.first, om.last = e, e
+		return
+	}
+
+	e.prev, e.next = mark.prev, mark
+	if mark.prev == nil {
+		om.first = e
+	} else {
+		mark.prev.next = e
+	}
+	mark.prev = e
+}
+
+// insertAfter links e after mark; when mark is nil, e becomes
+// the only element.
+func (om *OrderedMap[K, V]) insertAfter(e, mark *entry[K, V]) {
+
+	if mark == nil {
+		om.first, om.last = e, e
+		return
+	}
+
+	e.prev, e.next = mark, mark.next
+	if mark.next == nil {
+		om.last = e
+	} else {
+		mark.next.prev = e
+	}
+	mark.next = e
+}
+
+// IsZero returns whether the map has no elements. It makes OrderedMap
+// work with the omitzero option of encoding/json struct field tags.
+func (om *OrderedMap[K, V]) IsZero() bool {
+
+	return om == nil || len(om.entries) == 0
+}
+
+// MarshalJSON encodes the map as JSON object keeping the order of the
+// elements. See EncodeJSON for which keys are supported. An uninitialised
+// map is encoded as null.
 func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
 
-	if om.pairs == nil {
+	if om.entries == nil {
 		return []byte("null"), nil
 	}
 
-	if _, ok := any(om.order[0]).(string); !ok {
-		return nil, ErrKeysMustBeStrings
+	var buf bytes.Buffer
+	if err := om.EncodeJSON(&buf); err != nil {
+		return nil, err
 	}
 
-	var buf bytes.Buffer
-	buf.WriteByte('{')
-	for i, key := range om.order {
-		if i > 0 {
-			buf.WriteByte(',')
+	return buf.Bytes(), nil
+}
+
+// EncodeJSON writes the map as JSON object to w, encoding the values
+// directly into w. Like encoding/json does for maps, keys must be of a
+// string kind, implement encoding.TextMarshaler, or be integers. Otherwise,
+// ErrKeysMustBeStrings is returned.
+func (om *OrderedMap[K, V]) EncodeJSON(w io.Writer) error {
+
+	var zero K
+	if !isJSONKeyType(reflect.TypeOf(&zero).Elem()) {
+		return fmt.Errorf("%w (have %T)", ErrKeysMustBeStrings, zero)
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(jsonValueWriter{bw})
+
+	_ = bw.WriteByte('{')
+	for e := om.first; e != nil; e = e.next {
+		if e != om.first {
+			_ = bw.WriteByte(',')
 		}
-		keyJSON, err := json.Marshal(key)
+
+		key, err := marshalKey(e.key)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		valueJSON, err := json.Marshal(om.pairs[key])
-		if err != nil {
-			return nil, err
+
+		if err := enc.Encode(key); err != nil {
+			return err
+		}
+		_ = bw.WriteByte(':')
+		if err := enc.Encode(e.value); err != nil {
+			return err
 		}
-		buf.Write(keyJSON)
-		buf.WriteByte(':')
-		buf.Write(valueJSON)
 	}
-	buf.WriteByte('}')
+	_ = bw.WriteByte('}')
 
-	return buf.Bytes(), nil
+	return bw.Flush()
 }
 
+// UnmarshalJSON decodes the JSON object data, keeping the order of its
+// members. Keys are converted like encoding/json does for maps: using
+// encoding.TextUnmarshaler when implemented, or, otherwise, keys of a
+// string kind or integers. JSON null leaves the map unchanged.
+//
+// When V is any, nested objects are decoded as *OrderedMap[string, any]
+// instead of map[string]any so that the order is kept throughout.
 func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
 
-	decoder := json.NewDecoder(strings.NewReader(string(data)))
-	omTmp := NewOrderedMap[K, V]()
+	decoder := json.NewDecoder(bytes.NewReader(data))
 
 	// opening brace
 	if t, err := decoder.Token(); err != nil {
 		return err
+	} else if t == nil {
+		return nil // null
 	} else if t != json.Delim('{') {
 		return fmt.Errorf("expected JSON object, got %v", t)
 	}
 
-	// key-value pairs (object)
+	omTmp := NewOrderedMap[K, V]()
+	omTmp.init()
+	if err := omTmp.decodeJSONMembers(decoder); err != nil {
+		return err
+	}
+
+	*om = *omTmp
+
+	return nil
+}
+
+// decodeJSONMembers decodes the key-value pairs of the JSON object
+// of which decoder read the opening brace, up to and including the
+// closing brace.
+func (om *OrderedMap[K, V]) decodeJSONMembers(decoder *json.Decoder) error {
+
+	decodeAny := isAny[V]()
+
 	for decoder.More() {
 		keyToken, err := decoder.Token()
 		if err != nil {
 			return fmt.Errorf("error reading key: %w", err)
 		}
-		if _, ok := keyToken.(string); !ok {
+		keyString, ok := keyToken.(string)
+		if !ok {
 			return fmt.Errorf("expected string key, got %v", keyToken)
 		}
 
+		key, err := unmarshalKey[K](keyString)
+		if err != nil {
+			return err
+		}
+
 		var value V
-		if err := decoder.Decode(&value); err != nil {
+		if decodeAny {
+			v, err := decodeJSONValue(decoder)
+			if err != nil {
+				return err
+			}
+			value, _ = v.(V) // V is any; only fails for null
+		} else if err := decoder.Decode(&value); err != nil {
 			return err
 		}
 
-		omTmp.Set(keyToken.(K), value)
+		om.Set(key, value)
 	}
 
 	// closing brace
@@ -188,7 +533,144 @@ func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("expected JSON object, got %v", t)
 	}
 
-	*om = *omTmp
-
 	return nil
 }
+
+// decodeJSONValue decodes the next JSON value read by decoder like
+// encoding/json does for any, except that objects are decoded as
+// *OrderedMap[string, any].
+func decodeJSONValue(decoder *json.Decoder) (any, error) {
+
+	t, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		om := NewOrderedMap[string, any]()
+		om.init()
+		if err := om.decodeJSONMembers(decoder); err != nil {
+			return nil, err
+		}
+		return om, nil
+	case json.Delim('['):
+		values := []any{}
+		for decoder.More() {
+			v, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		if _, err := decoder.Token(); err != nil { // closing bracket
+			return nil, err
+		}
+		return values, nil
+	}
+
+	return t, nil
+}
+
+// isAny returns whether V is the empty interface.
+func isAny[V any]() bool {
+
+	return reflect.TypeFor[V]() == reflect.TypeFor[any]()
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// jsonValueWriter writes what json.Encoder encodes without the
+// newline it terminates each value with.
+type jsonValueWriter struct {
+	w io.Writer
+}
+
+func (vw jsonValueWriter) Write(p []byte) (int, error) {
+
+	if _, err := vw.w.Write(bytes.TrimSuffix(p, []byte("\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// isJSONKeyType returns whether t can be used as key of a JSON object.
+func isJSONKeyType(t reflect.Type) bool {
+
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return t.Implements(textMarshalerType)
+}
+
+// marshalKey returns key as text, for example, as name of a JSON object
+// member.
+func marshalKey(key any) (string, error) {
+
+	v := reflect.ValueOf(key)
+
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+		text, err := tm.MarshalText()
+		if err != nil {
+			return "", fmt.Errorf("json: error calling MarshalText for type %T: %w", key, err)
+		}
+		return string(text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("%w (have %T)", ErrKeysMustBeStrings, key)
+}
+
+// unmarshalKey converts name, for example, of a JSON object member, to K.
+func unmarshalKey[K comparable](name string) (K, error) {
+
+	var key K
+	kv := reflect.ValueOf(&key).Elem()
+	kt := kv.Type()
+
+	if reflect.PointerTo(kt).Implements(textUnmarshalerType) {
+		if err := kv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(name)); err != nil {
+			return key, err
+		}
+		return key, nil
+	}
+
+	switch kt.Kind() {
+	case reflect.String:
+		kv.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, kt.Bits())
+		if err != nil {
+			return key, &json.UnmarshalTypeError{Value: "number " + name, Type: kt}
+		}
+		kv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, kt.Bits())
+		if err != nil {
+			return key, &json.UnmarshalTypeError{Value: "number " + name, Type: kt}
+		}
+		kv.SetUint(n)
+	default:
+		return key, fmt.Errorf("%w (have %s)", ErrKeysMustBeStrings, kt)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// sliceOrderedMap is the previous implementation of OrderedMap keeping the
+// order in a slice. It is used to benchmark OrderedMap against.
+type sliceOrderedMap[K comparable, V any] struct {
+	pairs map[K]V
+	order []K
+}
+
+func (om *sliceOrderedMap[K, V]) init() {
+
+	om.pairs = map[K]V{}
+	om.order = []K{}
+}
+
+// Count returns the number of elements in the map.
+func (om *sliceOrderedMap[K, V]) Count() int {
+	return len(om.order)
+}
+
+// Set key in OrderedMap to value. Previously stored values
+// are overwritten, but the order does not change.
+func (om *sliceOrderedMap[K, V]) Set(key K, value V) {
+
+	if om.pairs == nil {
+		om.init()
+	}
+
+	om.pairs[key] = value
+	if !om.has(key) {
+		om.order = append(om.order, key)
+	}
+}
+
+// Delete deletes the element with the specified key from
+// the OrderedMap.
+func (om *sliceOrderedMap[K, V]) Delete(key K) {
+
+	om.order = slices.DeleteFunc(om.order, func(t K) bool {
+		return t == key
+	})
+
+	delete(om.pairs, key)
+}
+
+// Keys returns keys as slice of string.
+func (om *sliceOrderedMap[K, V]) Keys() []K {
+
+	return om.order
+}
+
+func (om *sliceOrderedMap[K, V]) values() []V {
+
+	res := make([]V, len(om.order))
+	for i, k := range om.order {
+		res[i] = om.pairs[k]
+	}
+
+	return res
+}
+
+// Values returns the values as slice of interfaces.
+func (om *sliceOrderedMap[K, V]) Values() []V {
+
+	return om.values()
+}
+
+// KeysValues returns the keys as slice of strings, and values as slice of interfaces.
+func (om *sliceOrderedMap[K, V]) KeysValues() ([]K, []V) {
+
+	return om.order, om.values()
+}
+
+// Has returns whether the map contains key.
+func (om *sliceOrderedMap[K, V]) Has(key K) bool {
+
+	return om.has(key)
+}
+
+func (om *sliceOrderedMap[K, V]) has(key K) bool {
+
+	for _, e := range om.order {
+		if e == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value returns the value for key and also whether it was found.
+// The bool is returned because value could be nil.
+func (om *sliceOrderedMap[K, V]) Value(key K) (V, bool) {
+
+	return om.pairs[key], om.has(key)
+}
+
+// orderedMap is implemented by both OrderedMap and sliceOrderedMap.
+type orderedMap[K comparable, V any] interface {
+	Set(key K, value V)
+	Has(key K) bool
+	Value(key K) (V, bool)
+	Delete(key K)
+}
+
+var benchmarkSizes = []int{100, 1_000, 10_000}
+
+func benchmarkOrderedMaps(b *testing.B, bench func(b *testing.B, n int, newMap func() orderedMap[string, int])) {
+
+	impls := []struct {
+		name   string
+		newMap func() orderedMap[string, int]
+	}{
+		{"OrderedMap", func() orderedMap[string, int] { return NewOrderedMap[string, int]() }},
+		{"slice", func() orderedMap[string, int] { return &sliceOrderedMap[string, int]{} }},
+	}
+
+	for _, n := range benchmarkSizes {
+		for _, impl := range impls {
+			b.Run(fmt.Sprintf("%s/n=%d", impl.name, n), func(b *testing.B) {
+				bench(b, n, impl.newMap)
+			})
+		}
+	}
+}
+
+func benchmarkKeys(n int) []string {
+
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+	return keys
+}
+
+func filledOrderedMap(keys []string, newMap func() orderedMap[string, int]) orderedMap[string, int] {
+
+	om := newMap()
+	for i, k := range keys {
+		om.Set(k, i)
+	}
+	return om
+}
+
+func BenchmarkOrderedMap_Set(b *testing.B) {
+	benchmarkOrderedMaps(b, func(b *testing.B, n int, newMap func() orderedMap[string, int]) {
+		keys := benchmarkKeys(n)
+		b.ResetTimer()
+
+		for range b.N {
+			filledOrderedMap(keys, newMap)
+		}
+	})
+}
+
+func BenchmarkOrderedMap_Has(b *testing.B) {
+	benchmarkOrderedMaps(b, func(b *testing.B, n int, newMap func() orderedMap[string, int]) {
+		keys := benchmarkKeys(n)
+		om := filledOrderedMap(keys, newMap)
+		b.ResetTimer()
+
+		for i := range b.N {
+			om.Has(keys[i%n])
+		}
+	})
+}
+
+func BenchmarkOrderedMap_Value(b *testing.B) {
+	benchmarkOrderedMaps(b, func(b *testing.B, n int, newMap func() orderedMap[string, int]) {
+		keys := benchmarkKeys(n)
+		om := filledOrderedMap(keys, newMap)
+		b.ResetTimer()
+
+		for i := range b.N {
+			om.Value(keys[i%n])
+		}
+	})
+}
+
+func BenchmarkOrderedMap_Delete(b *testing.B) {
+	benchmarkOrderedMaps(b, func(b *testing.B, n int, newMap func() orderedMap[string, int]) {
+		keys := benchmarkKeys(n)
+		b.ResetTimer()
+
+		for range b.N {
+			b.StopTimer()
+			om := filledOrderedMap(keys, newMap)
+			b.StartTimer()
+
+			for _, k := range keys {
+				om.Delete(k)
+			}
+		}
+	})
+}
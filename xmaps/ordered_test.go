@@ -1,10 +1,14 @@
-// Copyright (c) 2023, 2024, Geert JM Vanderkelen
+// Copyright (c) 2023, 2024, 2026, Geert JM Vanderkelen
 
 package xmaps
 
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/golistic/xgo/xt"
@@ -116,6 +120,32 @@ func TestOrderedMap(t *testing.T) {
 		xt.Eq(t, false, have)
 	})
 
+	t.Run("delete keeps order of remaining elements", func(t *testing.T) {
+
+		om := OrderedMap[string, int]{}
+		for i, k := range []string{"a", "b", "c", "d", "e"} {
+			om.Set(k, i)
+		}
+
+		om.Delete("a") // first
+		om.Delete("e") // last
+		om.Delete("c") // middle
+		xt.Eq(t, []string{"b", "d"}, om.Keys())
+		xt.Eq(t, []int{1, 3}, om.Values())
+
+		om.Set("a", 10) // added again, goes last
+		xt.Eq(t, []string{"b", "d", "a"}, om.Keys())
+
+		om.Delete("b")
+		om.Delete("d")
+		om.Delete("a")
+		xt.Eq(t, 0, om.Count())
+		xt.Eq(t, 0, len(om.Keys()))
+
+		om.Set("z", 26)
+		xt.Eq(t, []string{"z"}, om.Keys())
+	})
+
 	t.Run("map[int] retrieve keys and values", func(t *testing.T) {
 
 		om := OrderedMap[int, any]{}
@@ -167,10 +197,10 @@ func TestOrderedMap(t *testing.T) {
 	})
 
 	t.Run("encoded to JSON keys must be strings", func(t *testing.T) {
-		om := NewOrderedMap[int, any]()
-		om.Set(1, "value")
+		om := NewOrderedMap[float64, any]()
+		om.Set(1.5, "value")
 		_, err := om.MarshalJSON()
-		xt.ErrorIs(t, err, ErrKeysMustBeStrings)
+		xt.ErrorIs(t, ErrKeysMustBeStrings, err)
 	})
 
 	t.Run("decoded from JSON errors with invalid keys", func(t *testing.T) {
@@ -181,3 +211,319 @@ func TestOrderedMap(t *testing.T) {
 		xt.Assert(t, errors.As(err, &syntaxErr))
 	})
 }
+
+type testKeyIP [4]byte
+
+func (ip testKeyIP) MarshalText() ([]byte, error) {
+
+	return fmt.Appendf(nil, "%d.%d.%d.%d", ip[0], ip[1], ip[2], ip[3]), nil
+}
+
+func (ip *testKeyIP) UnmarshalText(text []byte) error {
+
+	_, err := fmt.Sscanf(string(text), "%d.%d.%d.%d", &ip[0], &ip[1], &ip[2], &ip[3])
+	return err
+}
+
+func TestOrderedMap_JSON(t *testing.T) {
+
+	t.Run("named string keys", func(t *testing.T) {
+		type name string
+
+		om := NewOrderedMap[name, int]()
+		om.Set("b", 2)
+		om.Set("a", 1)
+
+		data, err := json.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, `{"b":2,"a":1}`, string(data))
+
+		have := NewOrderedMap[name, int]()
+		xt.OK(t, json.Unmarshal(data, have))
+		xt.Eq(t, []name{"b", "a"}, have.Keys())
+	})
+
+	t.Run("integer keys", func(t *testing.T) {
+		om := NewOrderedMap[int, string]()
+		om.Set(10, "ten")
+		om.Set(-1, "minus one")
+
+		data, err := json.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, `{"10":"ten","-1":"minus one"}`, string(data))
+
+		have := NewOrderedMap[int, string]()
+		xt.OK(t, json.Unmarshal(data, have))
+		xt.Eq(t, []int{10, -1}, have.Keys())
+	})
+
+	t.Run("integer keys out of range", func(t *testing.T) {
+		om := NewOrderedMap[uint8, string]()
+		err := json.Unmarshal([]byte(`{"256":"overflow"}`), om)
+		var typeErr *json.UnmarshalTypeError
+		xt.Assert(t, errors.As(err, &typeErr))
+
+		err = json.Unmarshal([]byte(`{"-1":"negative"}`), om)
+		xt.Assert(t, errors.As(err, &typeErr))
+	})
+
+	t.Run("text marshaler keys", func(t *testing.T) {
+		om := NewOrderedMap[testKeyIP, bool]()
+		om.Set(testKeyIP{192, 168, 1, 1}, true)
+		om.Set(testKeyIP{10, 0, 0, 1}, false)
+
+		data, err := json.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, `{"192.168.1.1":true,"10.0.0.1":false}`, string(data))
+
+		have := NewOrderedMap[testKeyIP, bool]()
+		xt.OK(t, json.Unmarshal(data, have))
+		xt.Eq(t, []testKeyIP{{192, 168, 1, 1}, {10, 0, 0, 1}}, have.Keys())
+	})
+
+	t.Run("values are not escaped twice", func(t *testing.T) {
+		om := NewOrderedMap[string, any]()
+		om.Set("html", "<b>")
+		om.Set("nested", map[string]int{"z": 1, "a": 2})
+
+		data, err := json.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, `{"html":"\u003cb\u003e","nested":{"a":2,"z":1}}`, string(data))
+	})
+
+	t.Run("empty after deleting all elements", func(t *testing.T) {
+		om := newTestOrderedMap("a", "b")
+		om.Delete("a")
+		om.Delete("b")
+
+		data, err := json.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, `{}`, string(data))
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var om OrderedMap[string, int]
+		data, err := json.Marshal(&om)
+		xt.OK(t, err)
+		xt.Eq(t, `null`, string(data))
+
+		have := newTestOrderedMap("a")
+		xt.OK(t, json.Unmarshal([]byte(`null`), have))
+		xt.Eq(t, []string{"a"}, have.Keys())
+	})
+
+	t.Run("omitzero", func(t *testing.T) {
+		type config struct {
+			Name    string                       `json:"name"`
+			Labels  *OrderedMap[string, string]  `json:"labels,omitzero"`
+			Servers OrderedMap[string, int]      `json:"servers,omitzero"`
+			Synced  *SyncOrderedMap[string, int] `json:"synced,omitzero"`
+		}
+
+		cfg := config{Name: "app", Labels: NewOrderedMap[string, string](), Synced: NewSyncOrderedMap[string, int]()}
+		data, err := json.Marshal(&cfg)
+		xt.OK(t, err)
+		xt.Eq(t, `{"name":"app"}`, string(data))
+
+		cfg.Labels.Set("env", "prod")
+		cfg.Servers.Set("web", 80)
+		cfg.Synced.Set("db", 5432)
+		data, err = json.Marshal(&cfg)
+		xt.OK(t, err)
+		xt.Eq(t, `{"name":"app","labels":{"env":"prod"},"servers":{"web":80},"synced":{"db":5432}}`, string(data))
+	})
+
+	t.Run("nested objects keep order", func(t *testing.T) {
+		doc := `{"z":{"b":1,"a":[{"y":true,"x":null}]},"empty":{},"list":[]}`
+
+		om := NewOrderedMap[string, any]()
+		xt.OK(t, json.Unmarshal([]byte(doc), om))
+
+		z, ok := om.Value("z")
+		xt.Assert(t, ok)
+		xt.Eq(t, []string{"b", "a"}, z.(*OrderedMap[string, any]).Keys())
+
+		data, err := json.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, doc, string(data))
+	})
+
+	t.Run("encode to writer", func(t *testing.T) {
+		var buf strings.Builder
+		xt.OK(t, newTestOrderedMap("x", "y").EncodeJSON(&buf))
+		xt.Eq(t, `{"x":1,"y":2}`, buf.String())
+	})
+}
+
+func newTestOrderedMap(keys ...string) *OrderedMap[string, int] {
+
+	om := NewOrderedMap[string, int]()
+	for i, k := range keys {
+		om.Set(k, i+1)
+	}
+	return om
+}
+
+func TestOrderedMap_iterators(t *testing.T) {
+	om := newTestOrderedMap("c", "a", "b")
+
+	t.Run("all", func(t *testing.T) {
+		var keys []string
+		var values []int
+		for k, v := range om.All() {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+		xt.Eq(t, []string{"c", "a", "b"}, keys)
+		xt.Eq(t, []int{1, 2, 3}, values)
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		var keys []string
+		for k := range om.Backward() {
+			keys = append(keys, k)
+		}
+		xt.Eq(t, []string{"b", "a", "c"}, keys)
+	})
+
+	t.Run("keys and values", func(t *testing.T) {
+		xt.Eq(t, []string{"c", "a", "b"}, slices.Collect(om.KeysSeq()))
+		xt.Eq(t, []int{1, 2, 3}, slices.Collect(om.ValuesSeq()))
+		xt.Eq(t, map[string]int{"a": 2, "b": 3, "c": 1}, maps.Collect(om.All()))
+	})
+
+	t.Run("break", func(t *testing.T) {
+		var keys []string
+		for k := range om.KeysSeq() {
+			keys = append(keys, k)
+			break
+		}
+		xt.Eq(t, []string{"c"}, keys)
+	})
+
+	t.Run("delete while iterating", func(t *testing.T) {
+		om := newTestOrderedMap("a", "b", "c", "d")
+		for k, v := range om.All() {
+			if v%2 == 0 {
+				om.Delete(k)
+			}
+		}
+		xt.Eq(t, []string{"a", "c"}, om.Keys())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var om OrderedMap[string, int]
+		for range om.All() {
+			t.Fatal("expected no elements")
+		}
+		for range om.Backward() {
+			t.Fatal("expected no elements")
+		}
+	})
+}
+
+func TestOrderedMap_copies(t *testing.T) {
+	om := newTestOrderedMap("a", "b")
+
+	keys := om.Keys()
+	keys[0] = "z"
+	values := om.Values()
+	values[0] = 26
+
+	xt.Eq(t, []string{"a", "b"}, om.Keys())
+	xt.Eq(t, []int{1, 2}, om.Values())
+	xt.Assert(t, !om.Has("z"))
+}
+
+func TestOrderedMap_positions(t *testing.T) {
+	om := newTestOrderedMap("a", "b", "c", "d", "e")
+
+	for i, exp := range []string{"a", "b", "c", "d", "e"} {
+		k, v, ok := om.At(i)
+		xt.Assert(t, ok)
+		xt.Eq(t, exp, k)
+		xt.Eq(t, i+1, v)
+		xt.Eq(t, i, om.IndexOf(exp))
+	}
+
+	_, _, ok := om.At(-1)
+	xt.Assert(t, !ok)
+	_, _, ok = om.At(5)
+	xt.Assert(t, !ok)
+	xt.Eq(t, -1, om.IndexOf("z"))
+
+	k, v, ok := om.First()
+	xt.Assert(t, ok)
+	xt.Eq(t, "a", k)
+	xt.Eq(t, 1, v)
+
+	k, v, ok = om.Last()
+	xt.Assert(t, ok)
+	xt.Eq(t, "e", k)
+	xt.Eq(t, 5, v)
+
+	t.Run("empty", func(t *testing.T) {
+		var om OrderedMap[string, int]
+		_, _, ok := om.First()
+		xt.Assert(t, !ok)
+		_, _, ok = om.Last()
+		xt.Assert(t, !ok)
+	})
+}
+
+func TestOrderedMap_reorder(t *testing.T) {
+	t.Run("move to front", func(t *testing.T) {
+		om := newTestOrderedMap("a", "b", "c")
+		xt.Assert(t, om.MoveToFront("c"))
+		xt.Eq(t, []string{"c", "a", "b"}, om.Keys())
+		xt.Assert(t, om.MoveToFront("c"))
+		xt.Eq(t, []string{"c", "a", "b"}, om.Keys())
+		xt.Assert(t, !om.MoveToFront("z"))
+
+		var backward []string
+		for k := range om.Backward() {
+			backward = append(backward, k)
+		}
+		xt.Eq(t, []string{"b", "a", "c"}, backward)
+	})
+
+	t.Run("move to back", func(t *testing.T) {
+		om := newTestOrderedMap("a", "b", "c")
+		xt.Assert(t, om.MoveToBack("a"))
+		xt.Eq(t, []string{"b", "c", "a"}, om.Keys())
+		xt.Assert(t, !om.MoveToBack("z"))
+	})
+
+	t.Run("move after", func(t *testing.T) {
+		om := newTestOrderedMap("a", "b", "c", "d")
+		xt.Assert(t, om.MoveAfter("a", "c"))
+		xt.Eq(t, []string{"b", "c", "a", "d"}, om.Keys())
+		xt.Assert(t, om.MoveAfter("b", "d"))
+		xt.Eq(t, []string{"c", "a", "d", "b"}, om.Keys())
+		xt.Assert(t, om.MoveAfter("b", "b"))
+		xt.Eq(t, []string{"c", "a", "d", "b"}, om.Keys())
+		xt.Assert(t, !om.MoveAfter("a", "z"))
+		xt.Assert(t, !om.MoveAfter("z", "a"))
+
+		k, _, _ := om.Last()
+		xt.Eq(t, "b", k)
+	})
+
+	t.Run("sort", func(t *testing.T) {
+		om := newTestOrderedMap("c", "a", "d", "b")
+		om.SortFunc(strings.Compare)
+		xt.Eq(t, []string{"a", "b", "c", "d"}, om.Keys())
+		xt.Eq(t, []int{2, 4, 1, 3}, om.Values())
+
+		// by value, descending
+		om.SortFunc(func(a, b string) int {
+			va, _ := om.Value(a)
+			vb, _ := om.Value(b)
+			return vb - va
+		})
+		xt.Eq(t, []string{"b", "d", "a", "c"}, om.Keys())
+
+		om.Set("e", 0)
+		xt.Eq(t, []string{"b", "d", "a", "c", "e"}, om.Keys())
+	})
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"io"
+	"iter"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SyncOrderedMap is an OrderedMap safe for concurrent use by multiple
+// goroutines, for example, when used as registry. Reads use a shared lock,
+// writes an exclusive one.
+//
+// Iterators iterate over a snapshot taken when iteration starts; the lock
+// is not held while yielding, so the map can be modified while iterating.
+//
+// The zero value is an empty map ready to use. A SyncOrderedMap must not
+// be copied after first use.
+type SyncOrderedMap[K comparable, V any] struct {
+	mu sync.RWMutex
+	om OrderedMap[K, V]
+}
+
+func NewSyncOrderedMap[K comparable, V any]() *SyncOrderedMap[K, V] {
+
+	return &SyncOrderedMap[K, V]{}
+}
+
+// Count returns the number of elements in the map.
+func (m *SyncOrderedMap[K, V]) Count() int {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.Count()
+}
+
+// Set key to value. Previously stored values are overwritten, but the
+// order does not change.
+func (m *SyncOrderedMap[K, V]) Set(key K, value V) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.om.Set(key, value)
+}
+
+// Delete deletes the element with the specified key.
+func (m *SyncOrderedMap[K, V]) Delete(key K) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.om.Delete(key)
+}
+
+// Has returns whether the map contains key.
+func (m *SyncOrderedMap[K, V]) Has(key K) bool {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.Has(key)
+}
+
+// Value returns the value for key and also whether it was found.
+func (m *SyncOrderedMap[K, V]) Value(key K) (V, bool) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.Value(key)
+}
+
+// Keys returns a copy of the keys in order.
+func (m *SyncOrderedMap[K, V]) Keys() []K {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.Keys()
+}
+
+// Values returns a copy of the values in order.
+func (m *SyncOrderedMap[K, V]) Values() []V {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.Values()
+}
+
+// IsZero returns whether the map has no elements; see OrderedMap.IsZero.
+func (m *SyncOrderedMap[K, V]) IsZero() bool {
+
+	if m == nil {
+		return true
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.IsZero()
+}
+
+// KeysValues returns a copy of the keys and of the values in order.
+func (m *SyncOrderedMap[K, V]) KeysValues() ([]K, []V) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.KeysValues()
+}
+
+// LoadOrStore returns the value stored for key when present. Otherwise,
+// it stores value and returns it. The bool is true when the value was
+// loaded, false when stored.
+func (m *SyncOrderedMap[K, V]) LoadOrStore(key K, value V) (V, bool) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if actual, ok := m.om.Value(key); ok {
+		return actual, true
+	}
+
+	m.om.Set(key, value)
+	return value, false
+}
+
+// LoadAndDelete deletes the element with key, returning its value and
+// whether it was present.
+func (m *SyncOrderedMap[K, V]) LoadAndDelete(key K) (V, bool) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.om.Value(key)
+	if ok {
+		m.om.Delete(key)
+	}
+
+	return value, ok
+}
+
+// CompareAndSwap stores new for key when the stored value is equal to old,
+// and returns whether it was swapped. Like sync.Map, the values must be of
+// a comparable type; CompareAndSwap panics otherwise.
+func (m *SyncOrderedMap[K, V]) CompareAndSwap(key K, old, new V) bool {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.om.Value(key)
+	if !ok || any(current) != any(old) {
+		return false
+	}
+
+	m.om.Set(key, new)
+	return true
+}
+
+// Update stores the value returned by fn for key, and returns it. The function
+// fn is called with the currently stored value and whether it was found,
+// while holding the lock; fn must not use m.
+func (m *SyncOrderedMap[K, V]) Update(key K, fn func(value V, ok bool) V) V {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value := fn(m.om.Value(key))
+	m.om.Set(key, value)
+
+	return value
+}
+
+// snapshot returns copies of the keys and values.
+func (m *SyncOrderedMap[K, V]) snapshot() ([]K, []V) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.KeysValues()
+}
+
+// All returns an iterator over a snapshot of the key-value pairs in order.
+func (m *SyncOrderedMap[K, V]) All() iter.Seq2[K, V] {
+
+	return func(yield func(K, V) bool) {
+		keys, values := m.snapshot()
+		for i, k := range keys {
+			if !yield(k, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over a snapshot of the key-value pairs
+// in reverse order.
+func (m *SyncOrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+
+	return func(yield func(K, V) bool) {
+		keys, values := m.snapshot()
+		for i := len(keys) - 1; i >= 0; i-- {
+			if !yield(keys[i], values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// KeysSeq returns an iterator over a snapshot of the keys in order.
+func (m *SyncOrderedMap[K, V]) KeysSeq() iter.Seq[K] {
+
+	return func(yield func(K) bool) {
+		for _, k := range m.Keys() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns an iterator over a snapshot of the values in order.
+func (m *SyncOrderedMap[K, V]) ValuesSeq() iter.Seq[V] {
+
+	return func(yield func(V) bool) {
+		for _, v := range m.Values() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON encodes the map as JSON object; see OrderedMap.MarshalJSON.
+func (m *SyncOrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.MarshalJSON()
+}
+
+// UnmarshalJSON replaces the content of the map with the JSON object
+// data; see OrderedMap.UnmarshalJSON.
+func (m *SyncOrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+
+	var om OrderedMap[K, V]
+	if err := om.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.om = om
+
+	return nil
+}
+
+// UnmarshalJSON5 replaces the content of the map with the JSON5 object
+// data; see OrderedMap.UnmarshalJSON5.
+func (m *SyncOrderedMap[K, V]) UnmarshalJSON5(data []byte) error {
+
+	var om OrderedMap[K, V]
+	if err := om.UnmarshalJSON5(data); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.om = om
+
+	return nil
+}
+
+// MarshalYAML encodes the map as YAML mapping; see OrderedMap.MarshalYAML.
+func (m *SyncOrderedMap[K, V]) MarshalYAML() (any, error) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.MarshalYAML()
+}
+
+// UnmarshalYAML replaces the content of the map with the YAML mapping
+// node; see OrderedMap.UnmarshalYAML.
+func (m *SyncOrderedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+
+	var om OrderedMap[K, V]
+	if err := om.UnmarshalYAML(node); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.om = om
+
+	return nil
+}
+
+// EncodeTOML writes the map as TOML document to w; see
+// OrderedMap.EncodeTOML.
+func (m *SyncOrderedMap[K, V]) EncodeTOML(w io.Writer) error {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.EncodeTOML(w)
+}
+
+// DecodeTOML replaces the content of the map with the TOML document read
+// from r; see OrderedMap.DecodeTOML.
+func (m *SyncOrderedMap[K, V]) DecodeTOML(r io.Reader) error {
+
+	var om OrderedMap[K, V]
+	if err := om.DecodeTOML(r); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.om = om
+
+	return nil
+}
+
+func (m *SyncOrderedMap[K, V]) objectEntries() ([]string, []any, error) {
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.om.objectEntries()
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestSyncOrderedMap(t *testing.T) {
+	t.Run("basic operations", func(t *testing.T) {
+		var m SyncOrderedMap[string, int]
+		m.Set("b", 2)
+		m.Set("a", 1)
+		m.Set("b", 20)
+
+		xt.Eq(t, 2, m.Count())
+		xt.Assert(t, m.Has("a"))
+		v, ok := m.Value("b")
+		xt.Assert(t, ok)
+		xt.Eq(t, 20, v)
+
+		keys, values := m.KeysValues()
+		xt.Eq(t, []string{"b", "a"}, keys)
+		xt.Eq(t, []int{20, 1}, values)
+
+		m.Delete("b")
+		xt.Eq(t, []string{"a"}, m.Keys())
+		xt.Eq(t, []int{1}, m.Values())
+	})
+
+	t.Run("load or store", func(t *testing.T) {
+		m := NewSyncOrderedMap[string, int]()
+
+		v, loaded := m.LoadOrStore("a", 1)
+		xt.Assert(t, !loaded)
+		xt.Eq(t, 1, v)
+
+		v, loaded = m.LoadOrStore("a", 2)
+		xt.Assert(t, loaded)
+		xt.Eq(t, 1, v)
+
+		v, loaded = m.LoadAndDelete("a")
+		xt.Assert(t, loaded)
+		xt.Eq(t, 1, v)
+		_, loaded = m.LoadAndDelete("a")
+		xt.Assert(t, !loaded)
+	})
+
+	t.Run("compare and swap", func(t *testing.T) {
+		m := NewSyncOrderedMap[string, int]()
+		xt.Assert(t, !m.CompareAndSwap("a", 0, 1), "missing key is not swapped")
+
+		m.Set("a", 1)
+		xt.Assert(t, !m.CompareAndSwap("a", 2, 3))
+		xt.Assert(t, m.CompareAndSwap("a", 1, 3))
+		v, _ := m.Value("a")
+		xt.Eq(t, 3, v)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		m := NewSyncOrderedMap[string, []string]()
+		add := func(value []string, _ bool) []string { return append(value, "x") }
+
+		xt.Eq(t, []string{"x"}, m.Update("a", add))
+		xt.Eq(t, []string{"x", "x"}, m.Update("a", add))
+	})
+
+	t.Run("iterators use snapshot", func(t *testing.T) {
+		m := NewSyncOrderedMap[string, int]()
+		m.Set("a", 1)
+		m.Set("b", 2)
+		m.Set("c", 3)
+
+		var keys []string
+		for k, v := range m.All() {
+			// would deadlock when the lock is held while yielding
+			m.Set(k+k, v)
+			keys = append(keys, k)
+		}
+		xt.Eq(t, []string{"a", "b", "c"}, keys)
+		xt.Eq(t, 6, m.Count())
+
+		keys = nil
+		for k := range m.Backward() {
+			m.Delete(k)
+			keys = append(keys, k)
+		}
+		xt.Eq(t, []string{"cc", "bb", "aa", "c", "b", "a"}, keys)
+		xt.Eq(t, 0, m.Count())
+
+		m.Set("z", 26)
+		xt.Eq(t, []string{"z"}, slices.Collect(m.KeysSeq()))
+		xt.Eq(t, []int{26}, slices.Collect(m.ValuesSeq()))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		m := NewSyncOrderedMap[string, int]()
+		xt.OK(t, json.Unmarshal([]byte(`{"b": 2, "a": 1}`), m))
+		xt.Eq(t, []string{"b", "a"}, m.Keys())
+
+		data, err := json.Marshal(m)
+		xt.OK(t, err)
+		xt.Eq(t, `{"b":2,"a":1}`, string(data))
+	})
+}
+
+// TestSyncOrderedMap_concurrent is meant to be run using the race
+// detector (go test -race).
+func TestSyncOrderedMap_concurrent(t *testing.T) {
+	m := NewSyncOrderedMap[string, int]()
+
+	const goroutines = 8
+	const n = 200
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range n {
+				key := fmt.Sprintf("key%d", i)
+				m.Update("counter", func(v int, _ bool) int { return v + 1 })
+				m.LoadOrStore(key, g)
+				m.CompareAndSwap(key, g, -1)
+				m.Has(key)
+				m.Value(key)
+				if i%10 == 0 {
+					for k := range m.All() {
+						_ = k
+					}
+					_, _ = json.Marshal(m)
+				}
+				if i%3 == 0 {
+					m.Delete(fmt.Sprintf("tmp%d-%d", g, i))
+				} else {
+					m.Set(fmt.Sprintf("tmp%d-%d", g, i), i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	counter, _ := m.Value("counter")
+	xt.Eq(t, goroutines*n, counter)
+
+	for i := range n {
+		v, ok := m.Value(fmt.Sprintf("key%d", i))
+		xt.Assert(t, ok)
+		xt.Eq(t, -1, v)
+	}
+}
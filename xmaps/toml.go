@@ -0,0 +1,510 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// object is implemented by OrderedMap and SyncOrderedMap so that nested
+// maps can be encoded keeping their order, whatever their type parameters.
+type object interface {
+	objectEntries() ([]string, []any, error)
+}
+
+var _ object = (*OrderedMap[string, any])(nil)
+
+// objectEntries returns the keys, as text, and the values in order.
+func (om *OrderedMap[K, V]) objectEntries() ([]string, []any, error) {
+
+	names := make([]string, 0, len(om.entries))
+	values := make([]any, 0, len(om.entries))
+
+	for e := om.first; e != nil; e = e.next {
+		name, err := marshalKey(e.key)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, name)
+		values = append(values, e.value)
+	}
+
+	return names, values, nil
+}
+
+// EncodeTOML writes the map as TOML document to w keeping the order of
+// the elements. Keys are converted to text like EncodeJSON does.
+//
+// Like TOML requires, key-value pairs are written before tables, and
+// elements which are nil are left out. Nested OrderedMap, as well as maps
+// with string keys (sorted by key), are encoded as tables, or as inline
+// tables when within an array.
+func (om *OrderedMap[K, V]) EncodeTOML(w io.Writer) error {
+
+	names, values, err := om.objectEntries()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := writeTOMLTable(&buf, nil, names, values); err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// DecodeTOML reads the TOML document from r and stores its key-value pairs
+// in the order they appear in the document. Keys are converted like
+// UnmarshalJSON does, and values are decoded using github.com/BurntSushi/toml.
+//
+// When V is any, tables are decoded as *OrderedMap[string, any] instead
+// of map[string]any so that the order is kept throughout.
+func (om *OrderedMap[K, V]) DecodeTOML(r io.Reader) error {
+
+	var doc map[string]toml.Primitive
+	md, err := toml.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return err
+	}
+
+	order := tomlKeyOrder(md)
+	decodeAny := isAny[V]()
+
+	omTmp := NewOrderedMap[K, V]()
+	omTmp.init()
+
+	for _, name := range order[""] {
+		key, err := unmarshalKey[K](name)
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if decodeAny {
+			var v any
+			if err := md.PrimitiveDecode(doc[name], &v); err != nil {
+				return err
+			}
+			value, _ = orderTOMLValue(v, tomlPath("", name), order).(V)
+		} else if err := md.PrimitiveDecode(doc[name], &value); err != nil {
+			return err
+		}
+
+		omTmp.Set(key, value)
+	}
+
+	*om = *omTmp
+
+	return nil
+}
+
+// tomlPath returns the path of the key name within the table at path.
+func tomlPath(path, name string) string {
+
+	return path + "." + strconv.Quote(name)
+}
+
+// tomlKeyOrder returns for each table, identified by its path, the names of
+// its keys in the order they appear in the document decoded into md. Tables
+// in arrays of tables are identified by their index.
+func tomlKeyOrder(md toml.MetaData) map[string][]string {
+
+	order := map[string][]string{}
+	seen := map[string]bool{}
+	tables := map[string]int{} // number of tables in arrays of tables
+
+	for _, key := range md.Keys() {
+		var path string
+		for i, name := range key {
+			child := tomlPath(path, name)
+			if !seen[child] {
+				seen[child] = true
+				order[path] = append(order[path], name)
+			}
+			path = child
+
+			if md.Type(key[:i+1]...) == "ArrayHash" {
+				if i == len(key)-1 {
+					tables[path]++
+				}
+				path += "[" + strconv.Itoa(tables[path]-1) + "]"
+			}
+		}
+	}
+
+	return order
+}
+
+// orderTOMLValue returns v, as decoded from TOML, with its tables
+// converted to *OrderedMap[string, any] using order. Keys not found in
+// order, for example of inline tables within arrays, are sorted.
+func orderTOMLValue(v any, path string, order map[string][]string) any {
+
+	switch v := v.(type) {
+	case map[string]any:
+		names := slices.Clone(order[path])
+		names = slices.DeleteFunc(names, func(name string) bool {
+			_, ok := v[name]
+			return !ok
+		})
+		var rest []string
+		for name := range v {
+			if !slices.Contains(names, name) {
+				rest = append(rest, name)
+			}
+		}
+		slices.Sort(rest)
+
+		om := NewOrderedMap[string, any]()
+		om.init()
+		for _, name := range append(names, rest...) {
+			om.Set(name, orderTOMLValue(v[name], tomlPath(path, name), order))
+		}
+		return om
+	case []map[string]any:
+		values := make([]any, len(v))
+		for i, table := range v {
+			values[i] = orderTOMLValue(table, path+"["+strconv.Itoa(i)+"]", order)
+		}
+		return values
+	case []any:
+		values := make([]any, len(v))
+		for i, value := range v {
+			values[i] = orderTOMLValue(value, path+"["+strconv.Itoa(i)+"]", order)
+		}
+		return values
+	}
+
+	return v
+}
+
+var reTOMLBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// writeTOMLTable writes the key-value pairs of the table at path, followed
+// by its tables and arrays of tables.
+func writeTOMLTable(w *bytes.Buffer, path []string, names []string, values []any) error {
+
+	type table struct {
+		name   string
+		value  reflect.Value
+		tables []reflect.Value // array of tables when not nil
+	}
+
+	var tables []table
+
+	for i, name := range names {
+		rv := tomlIndirect(reflect.ValueOf(values[i]))
+		switch {
+		case !rv.IsValid():
+			continue // TOML has no null
+		case isTOMLTable(rv):
+			tables = append(tables, table{name: name, value: rv})
+			continue
+		case isTOMLArrayOfTables(rv):
+			t := table{name: name}
+			for j := range rv.Len() {
+				t.tables = append(t.tables, tomlIndirect(rv.Index(j)))
+			}
+			tables = append(tables, t)
+			continue
+		}
+
+		_, _ = w.WriteString(tomlKey(name) + " = ")
+		if err := writeTOMLValue(w, rv); err != nil {
+			return fmt.Errorf("toml: key %s: %w", strings.Join(append(path, name), "."), err)
+		}
+		_ = w.WriteByte('\n')
+	}
+
+	for _, t := range tables {
+		tablePath := append(slices.Clip(path), t.name)
+		header := make([]string, len(tablePath))
+		for i, name := range tablePath {
+			header[i] = tomlKey(name)
+		}
+
+		if t.tables == nil {
+			if err := writeTOMLHeader(w, "["+strings.Join(header, ".")+"]", tablePath, t.value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, rv := range t.tables {
+			if err := writeTOMLHeader(w, "[["+strings.Join(header, ".")+"]]", tablePath, rv); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeTOMLHeader writes the table header followed by the table rv.
+func writeTOMLHeader(w *bytes.Buffer, header string, path []string, rv reflect.Value) error {
+
+	if w.Len() > 0 {
+		_ = w.WriteByte('\n')
+	}
+	_, _ = w.WriteString(header + "\n")
+
+	names, values, err := tomlTableEntries(rv)
+	if err != nil {
+		return err
+	}
+
+	return writeTOMLTable(w, path, names, values)
+}
+
+// writeTOMLValue writes rv as TOML value.
+func writeTOMLValue(w *bytes.Buffer, rv reflect.Value) error {
+
+	rv = tomlIndirect(rv)
+	if !rv.IsValid() {
+		return fmt.Errorf("cannot encode nil")
+	}
+
+	switch v := rv.Interface().(type) {
+	case time.Time:
+		_, _ = w.WriteString(tomlDatetime(v))
+		return nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return err
+		}
+		_, _ = w.WriteString(tomlString(string(text)))
+		return nil
+	}
+
+	if isTOMLTable(rv) {
+		names, values, err := tomlTableEntries(rv)
+		if err != nil {
+			return err
+		}
+		_ = w.WriteByte('{')
+		first := true
+		for i, name := range names {
+			if !tomlIndirect(reflect.ValueOf(values[i])).IsValid() {
+				continue
+			}
+			if !first {
+				_ = w.WriteByte(',')
+			}
+			first = false
+			_, _ = w.WriteString(" " + tomlKey(name) + " = ")
+			if err := writeTOMLValue(w, reflect.ValueOf(values[i])); err != nil {
+				return err
+			}
+		}
+		_, _ = w.WriteString(" }")
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		_, _ = w.WriteString(tomlString(rv.String()))
+	case reflect.Bool:
+		_, _ = w.WriteString(strconv.FormatBool(rv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, _ = w.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return fmt.Errorf("integer %d is out of range", rv.Uint())
+		}
+		_, _ = w.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		_, _ = w.WriteString(tomlFloat(rv.Float(), rv.Type().Bits()))
+	case reflect.Slice, reflect.Array:
+		_ = w.WriteByte('[')
+		for i := range rv.Len() {
+			if i > 0 {
+				_, _ = w.WriteString(", ")
+			}
+			if err := writeTOMLValue(w, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		_ = w.WriteByte(']')
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type())
+	}
+
+	return nil
+}
+
+var objectType = reflect.TypeFor[object]()
+
+// tomlIndirect returns the value rv points to, or the value stored in rv
+// when it is an interface. It returns the zero Value when rv is nil. Maps
+// implementing object are returned as pointer.
+func tomlIndirect(rv reflect.Value) reflect.Value {
+
+	for rv.IsValid() {
+		if rv.Type().Implements(objectType) {
+			break
+		}
+		if reflect.PointerTo(rv.Type()).Implements(objectType) {
+			p := reflect.New(rv.Type())
+			p.Elem().Set(rv)
+			return p
+		}
+		if rv.Kind() != reflect.Pointer && rv.Kind() != reflect.Interface {
+			break
+		}
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+
+	return rv
+}
+
+// isTOMLTable returns whether rv is encoded as TOML table: an OrderedMap,
+// SyncOrderedMap, or a map with string keys.
+func isTOMLTable(rv reflect.Value) bool {
+
+	if _, ok := rv.Interface().(object); ok {
+		return true
+	}
+
+	return rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String
+}
+
+// isTOMLArrayOfTables returns whether rv is a non-empty slice or array
+// holding only tables.
+func isTOMLArrayOfTables(rv reflect.Value) bool {
+
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Len() == 0 {
+		return false
+	}
+
+	for i := range rv.Len() {
+		if elem := tomlIndirect(rv.Index(i)); !elem.IsValid() || !isTOMLTable(elem) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tomlTableEntries returns the names and values of the table rv. Keys of
+// maps are sorted.
+func tomlTableEntries(rv reflect.Value) ([]string, []any, error) {
+
+	if o, ok := rv.Interface().(object); ok {
+		return o.objectEntries()
+	}
+
+	keys := rv.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	names := make([]string, len(keys))
+	values := make([]any, len(keys))
+	for i, k := range keys {
+		names[i] = k.String()
+		values[i] = rv.MapIndex(k).Interface()
+	}
+
+	return names, values, nil
+}
+
+// tomlKey returns name as bare key when possible, and quoted otherwise.
+func tomlKey(name string) string {
+
+	if reTOMLBareKey.MatchString(name) {
+		return name
+	}
+
+	return tomlString(name)
+}
+
+// tomlString returns s as TOML basic string.
+func tomlString(s string) string {
+
+	var b strings.Builder
+	b.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				_, _ = fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// tomlDatetime returns t as TOML offset date-time, or as local date-time,
+// date, or time when decoded as such by github.com/BurntSushi/toml.
+func tomlDatetime(t time.Time) string {
+
+	switch t.Location().String() {
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case "date-local":
+		return t.Format(time.DateOnly)
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+// tomlFloat returns f as TOML float, which always has a fractional part
+// or an exponent.
+func tomlFloat(f float64, bitSize int) string {
+
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+
+	return s
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestOrderedMap_TOML(t *testing.T) {
+
+	t.Run("round trip keeps order", func(t *testing.T) {
+		doc := `title = "Example"
+owner.name = "Tom"
+ports = [8001, 8000]
+ratio = 0.5
+created = 2026-10-18T09:30:00Z
+day = 2026-10-18
+
+[servers]
+
+[servers.beta]
+ip = "10.0.0.2"
+role = "backend"
+
+[servers.alpha]
+ip = "10.0.0.1"
+role = "frontend"
+
+[[products]]
+name = "Nail"
+sku = 284758393
+
+[[products]]
+sku = 738594937
+name = "Hammer"
+`
+		om := NewOrderedMap[string, any]()
+		xt.OK(t, om.DecodeTOML(strings.NewReader(doc)))
+		xt.Eq(t, []string{"title", "owner", "ports", "ratio", "created", "day", "servers", "products"}, om.Keys())
+
+		servers, _ := om.Value("servers")
+		xt.Eq(t, []string{"beta", "alpha"}, servers.(*OrderedMap[string, any]).Keys())
+
+		products, _ := om.Value("products")
+		xt.Eq(t, []string{"sku", "name"}, products.([]any)[1].(*OrderedMap[string, any]).Keys())
+
+		var buf strings.Builder
+		xt.OK(t, om.EncodeTOML(&buf))
+		xt.Eq(t, `title = "Example"
+ports = [8001, 8000]
+ratio = 0.5
+created = 2026-10-18T09:30:00Z
+day = 2026-10-18
+
+[owner]
+name = "Tom"
+
+[servers]
+
+[servers.beta]
+ip = "10.0.0.2"
+role = "backend"
+
+[servers.alpha]
+ip = "10.0.0.1"
+role = "frontend"
+
+[[products]]
+name = "Nail"
+sku = 284758393
+
+[[products]]
+sku = 738594937
+name = "Hammer"
+`, buf.String())
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		type server struct {
+			IP string `toml:"ip"`
+		}
+
+		om := NewOrderedMap[string, server]()
+		xt.OK(t, om.DecodeTOML(strings.NewReader("[web]\nip = \"10.0.0.2\"\n[db]\nip = \"10.0.0.1\"\n")))
+		xt.Eq(t, []string{"web", "db"}, om.Keys())
+		xt.Eq(t, []server{{IP: "10.0.0.2"}, {IP: "10.0.0.1"}}, om.Values())
+	})
+
+	t.Run("encode values", func(t *testing.T) {
+		inner := NewOrderedMap[string, any]()
+		inner.Set("z", 1)
+		inner.Set("a", nil)
+		inner.Set("b", "two")
+
+		om := NewOrderedMap[string, any]()
+		om.Set("quoted key", "line\n\"quoted\"\t\x01")
+		om.Set("float", 3.0)
+		om.Set("nothing", nil)
+		om.Set("when", time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC))
+		om.Set("mixed", []any{"a", inner})
+		om.Set("tables", []any{inner, map[string]int{"y": 2, "x": 1}})
+		om.Set("plain", map[string]bool{"on": true, "off": false})
+
+		var buf strings.Builder
+		xt.OK(t, om.EncodeTOML(&buf))
+		xt.Eq(t, `"quoted key" = "line\n\"quoted\"\t\u0001"
+float = 3.0
+when = 2026-10-18T09:30:00Z
+mixed = ["a", { z = 1, b = "two" }]
+
+[[tables]]
+z = 1
+b = "two"
+
+[[tables]]
+x = 1
+y = 2
+
+[plain]
+off = false
+on = true
+`, buf.String())
+
+		xt.OK(t, NewOrderedMap[string, any]().DecodeTOML(strings.NewReader(buf.String())))
+	})
+
+	t.Run("unsupported value", func(t *testing.T) {
+		om := NewOrderedMap[string, any]()
+		om.Set("ch", make(chan int))
+
+		var buf strings.Builder
+		xt.KO(t, om.EncodeTOML(&buf))
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		om := NewOrderedMap[string, any]()
+		xt.KO(t, om.DecodeTOML(strings.NewReader("key = ")))
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML encodes the map as YAML mapping keeping the order of the
+// elements. It implements the yaml.Marshaler interface of gopkg.in/yaml.v3.
+// An uninitialised map is encoded as null.
+func (om *OrderedMap[K, V]) MarshalYAML() (any, error) {
+
+	if om.entries == nil {
+		return nil, nil
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	for e := om.first; e != nil; e = e.next {
+		var key, value yaml.Node
+		if err := key.Encode(e.key); err != nil {
+			return nil, err
+		}
+		if err := value.Encode(e.value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &key, &value)
+	}
+
+	return node, nil
+}
+
+// UnmarshalYAML decodes the YAML mapping node, keeping the order of its
+// keys. It implements the yaml.Unmarshaler interface of gopkg.in/yaml.v3.
+// Merge keys ("<<") are supported, and null leaves the map unchanged.
+//
+// When V is any, nested mappings are decoded as *OrderedMap[string, any]
+// instead of map[string]any so that the order is kept throughout.
+func (om *OrderedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+
+	node = yamlResolve(node)
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	omTmp := NewOrderedMap[K, V]()
+	omTmp.init()
+	if err := omTmp.decodeYAMLMapping(node); err != nil {
+		return err
+	}
+
+	*om = *omTmp
+
+	return nil
+}
+
+// decodeYAMLMapping adds the key-value pairs of the mapping node to om.
+func (om *OrderedMap[K, V]) decodeYAMLMapping(node *yaml.Node) error {
+
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("yaml: line %d: cannot unmarshal %s into %T", node.Line, node.ShortTag(), om)
+	}
+
+	decodeAny := isAny[V]()
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == "!!merge" {
+			if err := om.mergeYAML(valueNode); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var key K
+		if err := keyNode.Decode(&key); err != nil {
+			return err
+		}
+
+		var value V
+		if decodeAny {
+			v, err := decodeYAMLValue(valueNode)
+			if err != nil {
+				return err
+			}
+			value, _ = v.(V) // V is any; only fails for null
+		} else if err := valueNode.Decode(&value); err != nil {
+			return err
+		}
+
+		om.Set(key, value)
+	}
+
+	return nil
+}
+
+// mergeYAML adds the key-value pairs of the mapping, or of the sequence of
+// mappings, node which are not yet in om. Like YAML merge keys specify, keys
+// set explicitly, and keys merged earlier, take precedence.
+func (om *OrderedMap[K, V]) mergeYAML(node *yaml.Node) error {
+
+	node = yamlResolve(node)
+
+	mappings := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		mappings = node.Content
+	}
+
+	for _, mapping := range mappings {
+		merged := NewOrderedMap[K, V]()
+		if err := merged.decodeYAMLMapping(yamlResolve(mapping)); err != nil {
+			return err
+		}
+		for k, v := range merged.All() {
+			if !om.Has(k) {
+				om.Set(k, v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// decodeYAMLValue decodes node like gopkg.in/yaml.v3 does for any, except
+// that mappings are decoded as *OrderedMap[string, any].
+func decodeYAMLValue(node *yaml.Node) (any, error) {
+
+	node = yamlResolve(node)
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		om := NewOrderedMap[string, any]()
+		om.init()
+		if err := om.decodeYAMLMapping(node); err != nil {
+			return nil, err
+		}
+		return om, nil
+	case yaml.SequenceNode:
+		values := make([]any, 0, len(node.Content))
+		for _, n := range node.Content {
+			v, err := decodeYAMLValue(n)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	}
+
+	var v any
+	if err := node.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// yamlResolve returns the node an alias refers to, or the content of
+// a document node.
+func yamlResolve(node *yaml.Node) *yaml.Node {
+
+	for {
+		switch {
+		case node.Kind == yaml.AliasNode && node.Alias != nil:
+			node = node.Alias
+		case node.Kind == yaml.DocumentNode && len(node.Content) == 1:
+			node = node.Content[0]
+		default:
+			return node
+		}
+	}
+}
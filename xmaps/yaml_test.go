@@ -0,0 +1,111 @@
+// Copyright (c) 2026, Geert JM Vanderkelen
+
+package xmaps
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/golistic/xgo/xt"
+)
+
+func TestOrderedMap_YAML(t *testing.T) {
+
+	t.Run("round trip keeps order", func(t *testing.T) {
+		doc := `name: app
+version: 3
+servers:
+    web:
+        port: 80
+        hosts:
+            - b.example.com
+            - a.example.com
+    db:
+        port: 5432
+labels: {}
+`
+		om := NewOrderedMap[string, any]()
+		xt.OK(t, yaml.Unmarshal([]byte(doc), om))
+		xt.Eq(t, []string{"name", "version", "servers", "labels"}, om.Keys())
+
+		servers, ok := om.Value("servers")
+		xt.Assert(t, ok)
+		xt.Eq(t, []string{"web", "db"}, servers.(*OrderedMap[string, any]).Keys())
+
+		data, err := yaml.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, doc, string(data))
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		type server struct {
+			Port int `yaml:"port"`
+		}
+
+		om := NewOrderedMap[string, server]()
+		xt.OK(t, yaml.Unmarshal([]byte("web: {port: 80}\ndb: {port: 5432}\n"), om))
+		xt.Eq(t, []string{"web", "db"}, om.Keys())
+		xt.Eq(t, []server{{Port: 80}, {Port: 5432}}, om.Values())
+	})
+
+	t.Run("integer keys", func(t *testing.T) {
+		om := NewOrderedMap[int, string]()
+		xt.OK(t, yaml.Unmarshal([]byte("3: three\n1: one\n"), om))
+		xt.Eq(t, []int{3, 1}, om.Keys())
+
+		data, err := yaml.Marshal(om)
+		xt.OK(t, err)
+		xt.Eq(t, "3: three\n1: one\n", string(data))
+	})
+
+	t.Run("anchors and merge keys", func(t *testing.T) {
+		doc := `defaults: &defaults
+  timeout: 10
+  retries: 3
+prod:
+  retries: 5
+  <<: *defaults
+  host: prod.example.com
+`
+		om := NewOrderedMap[string, any]()
+		xt.OK(t, yaml.Unmarshal([]byte(doc), om))
+
+		prod, _ := om.Value("prod")
+		keys, values := prod.(*OrderedMap[string, any]).KeysValues()
+		xt.Eq(t, []string{"retries", "timeout", "host"}, keys)
+		xt.Eq(t, []any{5, 10, "prod.example.com"}, values)
+	})
+
+	t.Run("nested in struct", func(t *testing.T) {
+		type config struct {
+			Env *OrderedMap[string, string] `yaml:"env"`
+		}
+
+		var cfg config
+		xt.OK(t, yaml.Unmarshal([]byte("env:\n    PATH: /bin\n    HOME: /root\n"), &cfg))
+		xt.Eq(t, []string{"PATH", "HOME"}, cfg.Env.Keys())
+	})
+
+	t.Run("null and empty", func(t *testing.T) {
+		var om OrderedMap[string, int]
+		data, err := yaml.Marshal(&om)
+		xt.OK(t, err)
+		xt.Eq(t, "null\n", string(data))
+
+		have := newTestOrderedMap("a")
+		xt.OK(t, yaml.Unmarshal([]byte("~"), have))
+		xt.Eq(t, []string{"a"}, have.Keys())
+
+		om.Set("a", 1)
+		om.Delete("a")
+		data, err = yaml.Marshal(&om)
+		xt.OK(t, err)
+		xt.Eq(t, "{}\n", string(data))
+	})
+
+	t.Run("not a mapping", func(t *testing.T) {
+		om := NewOrderedMap[string, any]()
+		xt.KO(t, yaml.Unmarshal([]byte("- a\n- b\n"), om))
+	})
+}
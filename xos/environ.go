@@ -4,6 +4,7 @@ package xos
 
 import (
 	"os"
+	"strings"
 )
 
 // EnvironMap goes through all environment variables, saving them
@@ -31,14 +32,14 @@ func environMapLookup(mapper func(name, value string) bool) map[string]string {
 	m := map[string]string{}
 
 	for _, envVar := range os.Environ() {
-		for i := 0; i < len(envVar); i++ {
-			if envVar[i] == '=' {
-				n := envVar[0:i]
-				v := envVar[i+1:]
-				if mapper == nil || mapper(n, v) {
-					m[n] = v
-				}
-			}
+		// values can contain '=', names cannot
+		n, v, ok := strings.Cut(envVar, "=")
+		if !ok {
+			continue
+		}
+
+		if mapper == nil || mapper(n, v) {
+			m[n] = v
 		}
 	}
 
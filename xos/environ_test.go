@@ -20,6 +20,13 @@ func TestEnvironMap(t *testing.T) {
 			xt.Eq(t, exp, value)
 		}
 	})
+
+	t.Run("value containing equal sign", func(t *testing.T) {
+		t.Setenv("XGO_TEST_ENVIRON", "a=b=c")
+
+		envmap := EnvironMap()
+		xt.Eq(t, "a=b=c", envmap["XGO_TEST_ENVIRON"])
+	})
 }
 
 func TestEnvironMapLookup(t *testing.T) {
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Names of the dialects supported out of the box.
+const (
+	DialectMySQL     = "mysql"
+	DialectPostgres  = "postgres"
+	DialectSQLite    = "sqlite"
+	DialectSQLServer = "sqlserver"
+)
+
+// Dialect parses and formats the data source names (DSN) of a database
+// driver. Additional dialects can be added using RegisterDialect.
+type Dialect interface {
+	// Name returns the name of the dialect, which is stored as Driver
+	// in the parsed DataSource.
+	Name() string
+	// Match returns whether name looks like a DSN of the dialect. It is
+	// used by ParseDSN to detect the dialect.
+	Match(name string) bool
+	// Parse parses name as DSN.
+	Parse(name string) (*DataSource, error)
+	// Format returns ds as DSN.
+	Format(ds *DataSource) string
+}
+
+var dialects = struct {
+	sync.RWMutex
+	registered []Dialect
+	aliases    map[string]string
+}{
+	// MySQL comes last, being the fallback when detecting
+	registered: []Dialect{sqlServerDialect{}, postgresDialect{}, sqliteDialect{}, mysqlDialect{}},
+	aliases: map[string]string{
+		"postgresql": DialectPostgres,
+		"pgx":        DialectPostgres,
+		"sqlite3":    DialectSQLite,
+		"mssql":      DialectSQLServer,
+	},
+}
+
+// RegisterDialect adds d to the dialects used by ParseDSN, or replaces the
+// dialect registered with the same name. Added dialects are tried before
+// those registered earlier when detecting the dialect.
+func RegisterDialect(d Dialect) {
+
+	dialects.Lock()
+	defer dialects.Unlock()
+
+	dialects.registered = slices.DeleteFunc(dialects.registered, func(r Dialect) bool {
+		return r.Name() == d.Name()
+	})
+	dialects.registered = slices.Insert(dialects.registered, 0, d)
+}
+
+// LookupDialect returns the dialect registered as name, which is case
+// insensitive. Driver names like "postgresql", "pgx", "sqlite3", and
+// "mssql" are accepted as aliases.
+func LookupDialect(name string) (Dialect, bool) {
+
+	dialects.RLock()
+	defer dialects.RUnlock()
+
+	name = strings.ToLower(name)
+	if alias, ok := dialects.aliases[name]; ok {
+		name = alias
+	}
+
+	for _, d := range dialects.registered {
+		if d.Name() == name {
+			return d, true
+		}
+	}
+
+	return nil, false
+}
+
+// detectDialect returns the first registered dialect matching name, or
+// MySQL when none matches.
+func detectDialect(name string) Dialect {
+
+	dialects.RLock()
+	defer dialects.RUnlock()
+
+	for _, d := range dialects.registered {
+		if d.Match(name) {
+			return d
+		}
+	}
+
+	return mysqlDialect{}
+}
@@ -1,5 +1,5 @@
 /*
- * Copyright (c) 2023, Geert JM Vanderkelen
+ * Copyright (c) 2023, 2026, Geert JM Vanderkelen
  */
 
 package xsql
@@ -9,99 +9,97 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
-
-	"github.com/golistic/xgo/xstrings"
 )
 
+const errInvalidDSN = "invalid data source name (%w)"
+
 var reDSNPassword = regexp.MustCompile(`^(.*):[^/]+?(@.*)$`)
 var dsnPasswordMask = "********"
 
-var reDSN = regexp.MustCompile(`(.*?)(?::(.*?))?@(\w+)\((.*?)\)(?:/([^?]+))?/?(\?)?(.*)?`)
+// dsnPasswordPlaceholder replaces passwords when formatting, and is then
+// replaced by the mask, since dialects might escape the mask.
+const dsnPasswordPlaceholder = "xsqlPasswordMask"
 
+// DataSource is a data source name (DSN) split in its parts. Which parts are
+// used, and how, depends on the dialect named by Driver.
+//
+// The password is masked when formatting using String, GoString, LogValue,
+// or MarshalJSON, but not when using fmt verbs such as %d, which print the
+// fields; use Masked for formatting with any verb.
 type DataSource struct {
+	// Driver is the name of the dialect, for example, DialectMySQL. When
+	// empty, MySQL is assumed.
 	Driver   string
 	User     string
 	Password string
+	// Protocol is "tcp" or "unix" for network connections, and "file" for
+	// SQLite databases.
 	Protocol string
-	Address  string
-	Schema   string
-	Options  url.Values
+	// Address is the host and port, the path to the Unix socket, or, for
+	// SQLite, the path to the database file.
+	Address string
+	// Schema is the name of the database.
+	Schema  string
+	Options url.Values
+
+	// form is the variant of the DSN, for example, the URL scheme, so that
+	// Format writes the DSN as it was parsed.
+	form string
+	// raw holds the parts as found when parsing, so that Format writes the
+	// DSN unchanged when nothing was modified.
+	raw dsnRaw
 }
 
 // String returns a simplified representation of d. Simplified means that
 // only username, protocol, address, and schema (if available) are included. The password is
 // obfuscated.
-func (d *DataSource) String() string {
+//
+// String uses a value receiver so that the password is also masked when
+// formatting a DataSource value, or a struct containing one, using %v.
+func (d DataSource) String() string {
 
-	n := fmt.Sprintf("%s:%s@%s(%s)", d.User, dsnPasswordMask, d.Protocol, d.Address)
-	if d.Schema != "" {
-		n += "/" + d.Schema
-	} else {
-		n += "/"
-	}
+	simple := d
+	simple.Password = dsnPasswordPlaceholder
+	simple.Options = nil
 
-	return n
+	return strings.Replace(simple.dialect().Format(&simple), dsnPasswordPlaceholder, dsnPasswordMask, 1)
 }
 
+// Format returns d as data source name using the dialect named by Driver.
 func (d *DataSource) Format() string {
 
-	var query string
-
-	if len(d.Options) != 0 {
-		query = "?" + d.Options.Encode()
-	}
+	return d.dialect().Format(d)
+}
 
-	n := fmt.Sprintf("%s:%s@%s(%s)",
-		d.User, d.Password, d.Protocol, d.Address)
-	if d.Schema != "" {
-		n += "/" + d.Schema
-	} else {
-		n += "/"
-	}
+// dialect returns the dialect named by Driver, or MySQL when Driver
+// is empty or unknown.
+func (d *DataSource) dialect() Dialect {
 
-	if query != "" {
-		n += query
+	if dialect, ok := LookupDialect(d.Driver); ok {
+		return dialect
 	}
 
-	return n
+	return mysqlDialect{}
 }
 
-// ParseDSN parsers the name as a data source name (DSN).
+// ParseDSN parsers the name as a data source name (DSN). The dialect is
+// detected using the registered dialects; when none matches, name is parsed
+// as MySQL DSN. Use ParseDSNDialect to parse using a particular dialect.
 func ParseDSN(name string) (*DataSource, error) {
-	errMsg := "invalid data source name (%w)"
-
-	m := reDSN.FindAllStringSubmatch(name, -1)
-	if m == nil {
-		return nil, fmt.Errorf(errMsg, fmt.Errorf("could not parse"))
-	}
-
-	protocol := strings.ToLower(m[0][3])
-	if !(protocol == "unix" || protocol == "tcp") {
-		return nil, fmt.Errorf(errMsg, fmt.Errorf("unsupported protocol '%s'", m[0][3]))
-	}
 
-	if err := validateSchema(m[0][5]); err != nil {
-		return nil, fmt.Errorf(errMsg, err)
-	}
+	return detectDialect(name).Parse(name)
+}
 
-	ds := &DataSource{
-		User:     m[0][1],
-		Password: m[0][2],
-		Protocol: protocol,
-		Address:  m[0][4],
-		Schema:   m[0][5],
-		Options:  url.Values{},
-	}
+// ParseDSNDialect parses name as data source name using the dialect
+// registered as dialect.
+func ParseDSNDialect(dialect, name string) (*DataSource, error) {
 
-	if xstrings.SliceHas(m[0], "?") {
-		var err error
-		ds.Options, err = url.ParseQuery(m[0][len(m[0])-1])
-		if err != nil {
-			return nil, fmt.Errorf(errMsg, fmt.Errorf("could not parse query part"))
-		}
+	d, ok := LookupDialect(dialect)
+	if !ok {
+		return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("unknown dialect '%s'", dialect))
 	}
 
-	return ds, nil
+	return d.Parse(name)
 }
 
 // ReplaceDSNDatabase takes dsn and replaces the database name. It
@@ -143,6 +141,8 @@ func SetDSNOptions(dsn string, options map[string]string) (string, error) {
 // When password is empty (not provided) the mask is added anyway.
 // When the DSN is something that was not a DSN, the mask itself is returned to
 // prevent possible mistakes.
+//
+// Deprecated: use MaskDSN, which handles all dialects.
 func MaskPasswordInDSN(dsn string) string {
 	var res = dsn
 
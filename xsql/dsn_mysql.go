@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// mysqlDialect handles DSNs as used by github.com/go-sql-driver/mysql:
+//
+//	[user[:password]@][protocol[(address)]]/name[?param=value&...]
+//
+// The DSN is split like the driver does: the database name follows the
+// last slash, the user information precedes the last '@' before it, and
+// the password follows the first colon. User and password are therefore
+// not escaped, while the database name is path-escaped and parameter values
+// are query-escaped.
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+
+	return DialectMySQL
+}
+
+func (d mysqlDialect) Match(name string) bool {
+
+	_, err := d.Parse(name)
+	return err == nil
+}
+
+func (mysqlDialect) Parse(name string) (*DataSource, error) {
+
+	slash := strings.LastIndexByte(name, '/')
+	if slash == -1 {
+		// leniently accept a missing slash when there is no database name
+		p := strings.LastIndexByte(name, ')')
+		if p == -1 || !(p == len(name)-1 || name[p+1] == '?') {
+			return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("could not parse"))
+		}
+		name = name[:p+1] + "/" + name[p+1:]
+		slash = p + 1
+	}
+
+	ds := &DataSource{
+		Driver: DialectMySQL,
+	}
+
+	// user information
+	head := name[:slash]
+	at := strings.LastIndexByte(head, '@')
+	if at != -1 {
+		var hasPassword bool
+		ds.User, ds.Password, hasPassword = strings.Cut(head[:at], ":")
+
+		// the password is not kept, so that it does not show up when
+		// printing the fields of ds; Format adds it again
+		text := ds.User + "@"
+		if hasPassword {
+			text = ds.User + ":@"
+		}
+		ds.raw.userinfo = rawPart{value: ds.User, text: text, ok: true}
+	} else {
+		ds.raw.userinfo = rawPart{ok: true}
+	}
+
+	// protocol and address
+	netAddr := head[at+1:]
+	protocol := netAddr
+	if open := strings.IndexByte(netAddr, '('); open != -1 {
+		if !strings.HasSuffix(netAddr, ")") {
+			return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("could not parse"))
+		}
+		protocol = netAddr[:open]
+		ds.Address = netAddr[open+1 : len(netAddr)-1]
+		ds.raw.address = rawPart{value: ds.Address, text: netAddr[open:], ok: true}
+	}
+
+	ds.Protocol = strings.ToLower(protocol)
+	if !(ds.Protocol == "" || ds.Protocol == "unix" || ds.Protocol == "tcp") {
+		return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("unsupported protocol '%s'", protocol))
+	}
+	ds.raw.protocol = rawPart{value: ds.Protocol, text: protocol, ok: true}
+
+	// database name and parameters
+	schema, query, hasQuery := strings.Cut(name[slash+1:], "?")
+
+	var err error
+	if ds.Schema, err = url.PathUnescape(schema); err != nil {
+		return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("could not parse"))
+	}
+	ds.raw.schema = rawPart{value: ds.Schema, text: schema, ok: true}
+
+	if err := validateSchema(ds.Schema); err != nil {
+		return nil, fmt.Errorf(errInvalidDSN, err)
+	}
+
+	if ds.Options, ds.raw.params, err = parseDSNQuery(query, false); err != nil {
+		return nil, fmt.Errorf(errInvalidDSN, err)
+	}
+
+	ds.raw.query = rawPart{value: formatDSNQuery(ds.Options, ds.raw.params, false), ok: true}
+	if hasQuery {
+		ds.raw.query.text = "?" + query
+	}
+
+	return ds, nil
+}
+
+func (mysqlDialect) Format(ds *DataSource) string {
+
+	var b strings.Builder
+
+	userinfo := ds.raw.userinfo.or(ds.User, func(user string) string {
+		if user == "" {
+			return ""
+		}
+		return user + "@"
+	})
+	if ds.Password != "" {
+		user := strings.TrimSuffix(strings.TrimSuffix(userinfo, "@"), ":")
+		userinfo = user + ":" + ds.Password + "@"
+	}
+	b.WriteString(userinfo)
+
+	b.WriteString(ds.raw.protocol.or(ds.Protocol, func(protocol string) string { return protocol }))
+	b.WriteString(ds.raw.address.or(ds.Address, func(address string) string {
+		if address == "" {
+			return ""
+		}
+		return "(" + address + ")"
+	}))
+
+	b.WriteString("/")
+	b.WriteString(ds.raw.schema.or(ds.Schema, url.PathEscape))
+
+	b.WriteString(ds.raw.query.or(formatDSNQuery(ds.Options, ds.raw.params, false), func(query string) string {
+		if query == "" {
+			return ""
+		}
+		return "?" + query
+	}))
+
+	return b.String()
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// dsnRaw holds parts of a DSN as found when parsing, so that Format writes
+// the parts which were not modified exactly as they were.
+type dsnRaw struct {
+	userinfo rawPart
+	protocol rawPart
+	address  rawPart
+	schema   rawPart
+	query    rawPart
+	params   []dsnParam
+	// keywords are the keys of a key=value DSN found when parsing, other
+	// than those stored as options.
+	keywords map[string]bool
+}
+
+// rawPart is the text of a part of a DSN, and the value it was parsed as.
+type rawPart struct {
+	value string
+	text  string
+	ok    bool
+}
+
+// or returns the text of r when value was not modified; otherwise, it
+// returns value formatted using format.
+func (r rawPart) or(value string, format func(string) string) string {
+
+	if r.ok && r.value == value {
+		return r.text
+	}
+
+	return format(value)
+}
+
+// dsnParam is an option as found in a DSN.
+type dsnParam struct {
+	key   string
+	value string
+	text  string
+}
+
+// parseDSNQuery parses query, options separated by ampersands, returning the
+// options and, in order, the params as found. Values are unescaped, and keys
+// only when unescapeKeys is true. Empty params are ignored, params without
+// '=' have an empty value, and params containing a semicolon are invalid.
+func parseDSNQuery(query string, unescapeKeys bool) (url.Values, []dsnParam, error) {
+
+	options := url.Values{}
+	var params []dsnParam
+
+	for _, text := range strings.Split(query, "&") {
+		if text == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(text, "=")
+		if strings.Contains(text, ";") {
+			return nil, nil, fmt.Errorf("could not parse query part")
+		}
+
+		if unescapeKeys {
+			var err error
+			if key, err = url.QueryUnescape(key); err != nil {
+				return nil, nil, fmt.Errorf("could not parse query part")
+			}
+		}
+
+		value, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not parse query part")
+		}
+
+		options.Add(key, value)
+		params = append(params, dsnParam{key: key, value: value, text: text})
+	}
+
+	return options, params, nil
+}
+
+// formatDSNOptions returns options encoded using encode, in the order of
+// params. Params whose value was not modified are returned as found. Values
+// added for keys found in params follow, and finally the options not found
+// in params, sorted by key.
+func formatDSNOptions(options url.Values, params []dsnParam, encode func(key, value string) string) []string {
+
+	var encoded []string
+	used := map[string]int{}
+	var keys []string
+
+	for _, p := range params {
+		if used[p.key] == 0 && !slices.Contains(keys, p.key) {
+			keys = append(keys, p.key)
+		}
+
+		values := options[p.key]
+		n := used[p.key]
+		if n >= len(values) {
+			continue // removed
+		}
+		used[p.key]++
+
+		if values[n] == p.value {
+			encoded = append(encoded, p.text)
+		} else {
+			encoded = append(encoded, encode(p.key, values[n]))
+		}
+	}
+
+	var added []string
+	for k := range options {
+		if !slices.Contains(keys, k) {
+			added = append(added, k)
+		}
+	}
+	slices.Sort(added)
+
+	for _, k := range append(keys, added...) {
+		for _, v := range options[k][min(used[k], len(options[k])):] {
+			encoded = append(encoded, encode(k, v))
+		}
+	}
+
+	return encoded
+}
+
+// formatDSNQuery returns options as query using formatDSNOptions. Values
+// are escaped, and keys only when escapeKeys is true.
+func formatDSNQuery(options url.Values, params []dsnParam, escapeKeys bool) string {
+
+	encoded := formatDSNOptions(options, params, func(key, value string) string {
+		if escapeKeys {
+			key = url.QueryEscape(key)
+		}
+		return key + "=" + url.QueryEscape(value)
+	})
+
+	return strings.Join(encoded, "&")
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// sqliteMemory is the file name SQLite uses for in-memory databases.
+const sqliteMemory = ":memory:"
+
+// sqliteDialect handles SQLite file names and URIs, as used by
+// github.com/mattn/go-sqlite3 and modernc.org/sqlite:
+//
+//	file:test.db?cache=shared&mode=rwc
+//	file:///var/lib/app/data.db?_pragma=foreign_keys(1)
+//	/var/lib/app/data.db
+//	:memory:
+//
+// The path of the database file is stored as Address, with Protocol "file".
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+
+	return DialectSQLite
+}
+
+func (sqliteDialect) Match(name string) bool {
+
+	if strings.HasPrefix(name, "file:") || name == sqliteMemory {
+		return true
+	}
+
+	path, _, _ := strings.Cut(name, "?")
+	if strings.ContainsAny(path, "@(") {
+		return false
+	}
+
+	for _, ext := range []string{".db", ".sqlite", ".sqlite3"} {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (sqliteDialect) Parse(name string) (*DataSource, error) {
+
+	ds := &DataSource{
+		Driver:   DialectSQLite,
+		Protocol: "file",
+	}
+
+	path, query, _ := strings.Cut(name, "?")
+
+	switch {
+	case strings.HasPrefix(path, "file://"):
+		ds.form = "file://"
+	case strings.HasPrefix(path, "file:"):
+		ds.form = "file:"
+	}
+
+	if ds.form != "" {
+		var err error
+		if path, err = url.PathUnescape(strings.TrimPrefix(path, ds.form)); err != nil {
+			return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("could not parse path"))
+		}
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("missing database file"))
+	}
+	ds.Address = path
+
+	var err error
+	if ds.Options, ds.raw.params, err = parseDSNQuery(query, true); err != nil {
+		return nil, fmt.Errorf(errInvalidDSN, err)
+	}
+
+	return ds, nil
+}
+
+func (sqliteDialect) Format(ds *DataSource) string {
+
+	n := ds.Address
+	if ds.form != "" {
+		n = ds.form + strings.NewReplacer("%", "%25", "?", "%3F", "#", "%23").Replace(n)
+	}
+
+	if query := formatDSNQuery(ds.Options, ds.raw.params, true); query != "" {
+		n += "?" + query
+	}
+
+	return n
+}
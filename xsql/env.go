@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/golistic/xgo/xos"
+)
+
+// ErrEnvNotSet is returned by DataSourceFromEnv when a required environment
+// variable is not set.
+var ErrEnvNotSet = errors.New("environment variable not set")
+
+// DataSourceFromEnv returns the data source configured using environment
+// variables starting with prefix and an underscore, for example, DB_HOST when
+// prefix is "DB". The following variables are used:
+//
+//	DSN       complete data source name; other variables override its parts
+//	DRIVER    name of the dialect; when not set, it is detected from DSN,
+//	          or defaults to MySQL when using the other variables
+//	HOST      host name or IP address
+//	PORT      TCP port
+//	SOCKET    path to the Unix socket, used instead of HOST and PORT
+//	USER      user name
+//	PASSWORD  password
+//	NAME      name of the database; for SQLite, the path to the database file
+//	OPTIONS   options as URL query, for example, "parseTime=true&tls=custom"
+//
+// Like Docker and Kubernetes do for secrets, each variable can be read from
+// the file named by the variable with suffix _FILE, for example,
+// DB_PASSWORD_FILE. Trailing new lines are removed from the content. It is
+// an error to set both the variable and its _FILE variant.
+//
+// When DSN is not set, HOST or SOCKET is required, or NAME for SQLite.
+// Errors name the variables involved, but never include the password or
+// the DSN.
+func DataSourceFromEnv(prefix string) (*DataSource, error) {
+
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	env := envLookup{prefix: prefix, environ: xos.EnvironMap()}
+	vars := map[string]string{}
+
+	for _, name := range []string{"DSN", "DRIVER", "HOST", "PORT", "SOCKET", "USER", "PASSWORD", "NAME", "OPTIONS"} {
+		value, err := env.lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+
+	var dialect string
+	if vars["DRIVER"] != "" {
+		d, ok := LookupDialect(vars["DRIVER"])
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown dialect '%s'", env.name("DRIVER"), vars["DRIVER"])
+		}
+		dialect = d.Name()
+	}
+
+	var ds *DataSource
+
+	if vars["DSN"] != "" {
+		var err error
+		if dialect == "" {
+			ds, err = ParseDSN(vars["DSN"])
+		} else {
+			ds, err = ParseDSNDialect(dialect, vars["DSN"])
+		}
+		if err != nil {
+			// parse errors might quote parts of the DSN, including the password
+			return nil, fmt.Errorf(errInvalidDSN, fmt.Errorf("could not parse %s", env.name("DSN")))
+		}
+		dialect = ds.Driver
+	} else {
+		if dialect == "" {
+			dialect = DialectMySQL
+		}
+		ds = &DataSource{
+			Driver:  dialect,
+			Options: map[string][]string{},
+		}
+
+		switch {
+		case dialect == DialectSQLite:
+			if vars["NAME"] == "" {
+				return nil, env.notSet("NAME")
+			}
+			ds.Protocol = "file"
+		case vars["HOST"] == "" && vars["SOCKET"] == "":
+			return nil, env.notSet("HOST")
+		default:
+			ds.Protocol = "tcp"
+		}
+	}
+
+	switch {
+	case dialect == DialectSQLite:
+		if vars["NAME"] != "" {
+			ds.Address = vars["NAME"]
+		}
+	case vars["SOCKET"] != "":
+		ds.Protocol = "unix"
+		ds.Address = vars["SOCKET"]
+	case vars["HOST"] != "" || vars["PORT"] != "":
+		host, port, err := net.SplitHostPort(ds.Address)
+		if err != nil {
+			host = ds.Address
+		}
+		if vars["HOST"] != "" {
+			host = vars["HOST"]
+		}
+		if vars["PORT"] != "" {
+			port = vars["PORT"]
+		}
+
+		ds.Protocol = "tcp"
+		ds.Address = host
+		if port != "" {
+			ds.Address = net.JoinHostPort(host, port)
+		}
+	}
+
+	if vars["USER"] != "" {
+		ds.User = vars["USER"]
+	}
+
+	if vars["PASSWORD"] != "" {
+		ds.Password = vars["PASSWORD"]
+	}
+
+	if vars["NAME"] != "" && dialect != DialectSQLite {
+		if err := validateSchema(vars["NAME"]); err != nil {
+			return nil, fmt.Errorf("%s: %w", env.name("NAME"), err)
+		}
+		ds.Schema = vars["NAME"]
+	}
+
+	if vars["OPTIONS"] != "" {
+		options, _, err := parseDSNQuery(vars["OPTIONS"], dialect != DialectMySQL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", env.name("OPTIONS"), err)
+		}
+		if ds.Options == nil {
+			ds.Options = map[string][]string{}
+		}
+		for k, v := range options {
+			ds.Options[k] = v
+		}
+	}
+
+	return ds, nil
+}
+
+// envLookup looks up environment variables starting with prefix.
+type envLookup struct {
+	prefix  string
+	environ map[string]string
+}
+
+func (e envLookup) name(name string) string {
+
+	return e.prefix + name
+}
+
+func (e envLookup) notSet(name string) error {
+
+	return fmt.Errorf("%w: %s (or %s_FILE)", ErrEnvNotSet, e.name(name), e.name(name))
+}
+
+// lookup returns the value of the environment variable name, or the content
+// of the file named by the variable name with suffix _FILE.
+func (e envLookup) lookup(name string) (string, error) {
+
+	name = e.name(name)
+	value, ok := e.environ[name]
+	file, okFile := e.environ[name+"_FILE"]
+
+	switch {
+	case ok && okFile:
+		return "", fmt.Errorf("both %s and %s_FILE are set", name, name)
+	case okFile:
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("%s_FILE: %w", name, err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	default:
+		return value, nil
+	}
+}
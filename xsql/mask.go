@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// reDSNUserinfo matches user information with password ("user:password@").
+var reDSNUserinfo = regexp.MustCompile(`[^\s:/@]+:[^\s@]*@`)
+
+// secretOptions are the keys, in lower case, of options holding secrets.
+var secretOptions = map[string]bool{
+	"password":    true,
+	"passwd":      true,
+	"pass":        true,
+	"pwd":         true,
+	"secret":      true,
+	"sslpassword": true,
+	"_auth_pass":  true,
+}
+
+// reDSNSecretOption matches an option holding a secret followed by '=',
+// for example, within a value that was not parsed as intended.
+var reDSNSecretOption = regexp.MustCompile(`(?i)(?:^|[^a-z0-9_])(?:password|passwd|pass|pwd|secret|sslpassword|_auth_pass)\s*=`)
+
+// MaskDSN masks the password within the data source name dsn, as well as
+// options holding secrets, such as "password=" in URLs or key=value DSNs.
+// The dialect is detected like ParseDSN does. This function is usually used
+// when displaying or logging the DSN.
+//
+// The mask itself is returned, so that nothing is leaked, when dsn cannot be
+// parsed, when the detected dialect is uncertain, or when any other part
+// of the DSN looks like it holds a secret.
+func MaskDSN(dsn string) string {
+
+	ds, err := ParseDSN(dsn)
+	if err != nil {
+		return dsnPasswordMask
+	}
+
+	// key=value pairs falling back to MySQL might be of another dialect,
+	// for example, an ADO connection string
+	if ds.Driver == DialectMySQL && rePostgresKeyword.MatchString(dsn) {
+		return dsnPasswordMask
+	}
+
+	if ds.hasHiddenSecret() {
+		return dsnPasswordMask
+	}
+
+	masked := ds.masked(dsnPasswordPlaceholder)
+
+	return strings.ReplaceAll(masked.Format(), dsnPasswordPlaceholder, dsnPasswordMask)
+}
+
+// isSecretOption returns whether the option key holds a secret,
+// for example, a password. The whole key is matched, ignoring case.
+func isSecretOption(key string) bool {
+
+	return secretOptions[strings.ToLower(key)]
+}
+
+// hasHiddenSecret returns whether a part of d other than the password and
+// options holding secrets looks like it contains a secret, for example, when
+// the DSN was not parsed as intended. Keys of other options are suspicious
+// when they mention a secret, like "mypassword" does.
+func (d DataSource) hasHiddenSecret() bool {
+
+	values := []string{d.User, d.Protocol, d.Address, d.Schema}
+	for k, v := range d.Options {
+		if isSecretOption(k) {
+			continue
+		}
+
+		lower := strings.ToLower(k)
+		if strings.Contains(lower, "pass") || strings.Contains(lower, "pwd") || strings.Contains(lower, "secret") {
+			return true
+		}
+		values = append(values, k)
+		values = append(values, v...)
+	}
+
+	for _, v := range values {
+		if reDSNSecretOption.MatchString(v) || reDSNUserinfo.MatchString(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// masked returns a copy of d with the password, when set, and values of
+// options holding secrets replaced by mask.
+func (d DataSource) masked(mask string) DataSource {
+
+	if d.Password != "" {
+		d.Password = mask
+	}
+
+	if d.Options == nil {
+		return d
+	}
+
+	options := make(url.Values, len(d.Options))
+	for k, values := range d.Options {
+		values = append([]string(nil), values...)
+		if isSecretOption(k) {
+			for i := range values {
+				values[i] = mask
+			}
+		}
+		options[k] = values
+	}
+	d.Options = options
+
+	return d
+}
+
+// GoString returns d as Go syntax, with the password masked, and is used
+// when formatting d using %#v.
+func (d DataSource) GoString() string {
+
+	masked := d.masked(dsnPasswordMask)
+
+	return fmt.Sprintf("xsql.DataSource{Driver:%q, User:%q, Password:%q, Protocol:%q, Address:%q, Schema:%q, Options:%#v}",
+		masked.Driver, masked.User, masked.Password, masked.Protocol, masked.Address, masked.Schema, masked.Options)
+}
+
+// MaskedDataSource wraps a DataSource implementing fmt.Formatter. DataSource
+// itself cannot: its Format method, returning the DSN, has the wrong
+// signature. Since fmt only uses String for the verbs %v, %s, %q, %x, and
+// %X, other verbs, like %d, print the fields of a DataSource, including the
+// password. Use DataSource.Masked to get a MaskedDataSource.
+type MaskedDataSource struct {
+	ds DataSource
+}
+
+// Masked returns d wrapped as MaskedDataSource, formatting d with the
+// password and options holding secrets masked using any verb.
+func (d DataSource) Masked() MaskedDataSource {
+
+	return MaskedDataSource{ds: d}
+}
+
+// Format implements fmt.Formatter. The verb %#v formats the data source
+// like GoString, %+v as DSN including options, and all other verbs use
+// String. Flags, width, and precision are applied to the result.
+func (m MaskedDataSource) Format(f fmt.State, verb rune) {
+
+	var s string
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		_, _ = io.WriteString(f, m.ds.GoString())
+		return
+	case verb == 'v' && f.Flag('+'):
+		masked := m.ds.masked(dsnPasswordPlaceholder)
+		s = strings.ReplaceAll(masked.Format(), dsnPasswordPlaceholder, dsnPasswordMask)
+	default:
+		s = m.ds.String()
+	}
+
+	_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), s)
+}
+
+// LogValue implements slog.LogValuer, logging d as group with the password
+// and options holding secrets masked.
+func (d DataSource) LogValue() slog.Value {
+
+	masked := d.masked(dsnPasswordPlaceholder)
+
+	attrs := []slog.Attr{
+		slog.String("driver", masked.Driver),
+		slog.String("user", masked.User),
+	}
+
+	if masked.Password != "" {
+		attrs = append(attrs, slog.String("password", dsnPasswordMask))
+	}
+
+	attrs = append(attrs,
+		slog.String("protocol", masked.Protocol),
+		slog.String("address", masked.Address),
+		slog.String("schema", masked.Schema),
+	)
+
+	if len(masked.Options) != 0 {
+		options := strings.ReplaceAll(masked.Options.Encode(), dsnPasswordPlaceholder, dsnPasswordMask)
+		attrs = append(attrs, slog.String("options", options))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// MarshalJSON implements json.Marshaler, encoding d like encoding/json
+// would, but with the password and options holding secrets masked.
+func (d DataSource) MarshalJSON() ([]byte, error) {
+
+	masked := d.masked(dsnPasswordMask)
+
+	return json.Marshal(struct {
+		Driver   string
+		User     string
+		Password string
+		Protocol string
+		Address  string
+		Schema   string
+		Options  url.Values
+	}{
+		Driver:   masked.Driver,
+		User:     masked.User,
+		Password: masked.Password,
+		Protocol: masked.Protocol,
+		Address:  masked.Address,
+		Schema:   masked.Schema,
+		Options:  masked.Options,
+	})
+}
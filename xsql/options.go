@@ -0,0 +1,576 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golistic/xgo/xconv"
+)
+
+// ErrUnknownOption is wrapped by the OptionError returned when an option is
+// not known by the dialect.
+var ErrUnknownOption = errors.New("unknown option")
+
+// OptionKind is the type of the value of an option.
+type OptionKind int
+
+const (
+	OptionString OptionKind = iota
+	OptionBool
+	OptionInt
+	// OptionDuration is a duration like "5s", as parsed by time.ParseDuration.
+	OptionDuration
+	// OptionSeconds is a duration as whole number of seconds.
+	OptionSeconds
+	// OptionMilliseconds is a duration as whole number of milliseconds.
+	OptionMilliseconds
+)
+
+// Option describes an option known by a dialect.
+type Option struct {
+	Name string
+	Kind OptionKind
+}
+
+// parse checks value, returning the duration for options holding
+// a duration.
+func (o Option) parse(value string) (time.Duration, error) {
+
+	switch o.Kind {
+	case OptionBool:
+		_, err := xconv.ParseBool(value)
+		return 0, err
+	case OptionInt:
+		_, err := strconv.Atoi(value)
+		return 0, err
+	case OptionDuration:
+		return time.ParseDuration(value)
+	case OptionSeconds, OptionMilliseconds:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, err
+		}
+		if o.Kind == OptionSeconds {
+			return time.Duration(n) * time.Second, nil
+		}
+		return time.Duration(n) * time.Millisecond, nil
+	default:
+		return 0, nil
+	}
+}
+
+// formatDuration returns d as value for the option, rounding up to the unit
+// of the option.
+func (o Option) formatDuration(d time.Duration) string {
+
+	switch o.Kind {
+	case OptionSeconds:
+		return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+	case OptionMilliseconds:
+		return strconv.FormatInt(int64((d+time.Millisecond-1)/time.Millisecond), 10)
+	default:
+		return d.String()
+	}
+}
+
+// OptionError describes an option which is not known, or has an
+// invalid value.
+type OptionError struct {
+	Key   string
+	Value string
+	// Suggestion is the known option Key is most likely a misspelling of.
+	Suggestion string
+	Err        error
+}
+
+func (e *OptionError) Error() string {
+
+	if !errors.Is(e.Err, ErrUnknownOption) {
+		return fmt.Sprintf("invalid value '%s' for option '%s' (%s)", e.Value, e.Key, e.Err)
+	}
+
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown option '%s' (did you mean '%s'?)", e.Key, e.Suggestion)
+	}
+
+	return fmt.Sprintf("unknown option '%s'", e.Key)
+}
+
+func (e *OptionError) Unwrap() error {
+
+	return e.Err
+}
+
+// accessorOptions are the options used by the typed accessors of
+// DataSource, such as Timeout.
+type accessorOptions struct {
+	timeout   string
+	parseTime string
+	tls       string
+	charset   string
+}
+
+var knownOptions = struct {
+	sync.RWMutex
+	options map[string]map[string]Option
+	// foldCase holds the dialects whose option names are case-insensitive
+	foldCase  map[string]bool
+	accessors map[string]accessorOptions
+}{
+	options: map[string]map[string]Option{
+		DialectMySQL: optionsByName(
+			Option{"allowAllFiles", OptionBool},
+			Option{"allowCleartextPasswords", OptionBool},
+			Option{"allowFallbackToPlaintext", OptionBool},
+			Option{"allowNativePasswords", OptionBool},
+			Option{"allowOldPasswords", OptionBool},
+			Option{"charset", OptionString},
+			Option{"checkConnLiveness", OptionBool},
+			Option{"clientFoundRows", OptionBool},
+			Option{"collation", OptionString},
+			Option{"columnsWithAlias", OptionBool},
+			Option{"compress", OptionBool},
+			Option{"connectionAttributes", OptionString},
+			Option{"interpolateParams", OptionBool},
+			Option{"loc", OptionString},
+			Option{"maxAllowedPacket", OptionInt},
+			Option{"multiStatements", OptionBool},
+			Option{"parseTime", OptionBool},
+			Option{"readTimeout", OptionDuration},
+			Option{"rejectReadOnly", OptionBool},
+			Option{"serverPubKey", OptionString},
+			Option{"timeTruncate", OptionDuration},
+			Option{"timeout", OptionDuration},
+			Option{"tls", OptionString},
+			Option{"writeTimeout", OptionDuration},
+			// system variables commonly set through the DSN
+			Option{"autocommit", OptionString},
+			Option{"sql_mode", OptionString},
+			Option{"time_zone", OptionString},
+			Option{"transaction_isolation", OptionString},
+		),
+		DialectPostgres: optionsByName(
+			Option{"application_name", OptionString},
+			Option{"channel_binding", OptionString},
+			Option{"client_encoding", OptionString},
+			Option{"connect_timeout", OptionSeconds},
+			Option{"fallback_application_name", OptionString},
+			Option{"gssencmode", OptionString},
+			Option{"host", OptionString},
+			Option{"hostaddr", OptionString},
+			Option{"keepalives", OptionInt},
+			Option{"keepalives_count", OptionInt},
+			Option{"keepalives_idle", OptionInt},
+			Option{"keepalives_interval", OptionInt},
+			Option{"krbsrvname", OptionString},
+			Option{"load_balance_hosts", OptionString},
+			Option{"options", OptionString},
+			Option{"passfile", OptionString},
+			Option{"port", OptionInt},
+			Option{"replication", OptionString},
+			Option{"search_path", OptionString},
+			Option{"service", OptionString},
+			Option{"sslcert", OptionString},
+			Option{"sslkey", OptionString},
+			Option{"sslmode", OptionString},
+			Option{"sslpassword", OptionString},
+			Option{"sslrootcert", OptionString},
+			Option{"sslsni", OptionInt},
+			Option{"target_session_attrs", OptionString},
+			Option{"tcp_user_timeout", OptionInt},
+			// pgx
+			Option{"default_query_exec_mode", OptionString},
+			Option{"pool_max_conns", OptionInt},
+			Option{"pool_min_conns", OptionInt},
+			Option{"statement_cache_capacity", OptionInt},
+		),
+		DialectSQLite: optionsByName(
+			Option{"cache", OptionString},
+			Option{"immutable", OptionBool},
+			Option{"mode", OptionString},
+			Option{"nolock", OptionBool},
+			Option{"vfs", OptionString},
+			Option{"_auto_vacuum", OptionString},
+			Option{"_busy_timeout", OptionMilliseconds},
+			Option{"_cache_size", OptionInt},
+			Option{"_case_sensitive_like", OptionBool},
+			Option{"_defer_foreign_keys", OptionBool},
+			Option{"_foreign_keys", OptionBool},
+			Option{"_journal_mode", OptionString},
+			Option{"_loc", OptionString},
+			Option{"_locking_mode", OptionString},
+			Option{"_mutex", OptionString},
+			Option{"_pragma", OptionString},
+			Option{"_query_only", OptionBool},
+			Option{"_recursive_triggers", OptionBool},
+			Option{"_secure_delete", OptionString},
+			Option{"_synchronous", OptionString},
+			Option{"_time_format", OptionString},
+			Option{"_txlock", OptionString},
+		),
+		DialectSQLServer: optionsByName(
+			Option{"app name", OptionString},
+			Option{"applicationintent", OptionString},
+			Option{"certificate", OptionString},
+			Option{"connection timeout", OptionSeconds},
+			Option{"dial timeout", OptionSeconds},
+			Option{"encrypt", OptionString},
+			Option{"failoverpartner", OptionString},
+			Option{"failoverport", OptionInt},
+			Option{"hostnameincertificate", OptionString},
+			Option{"keepalive", OptionSeconds},
+			Option{"log", OptionInt},
+			Option{"multisubnetfailover", OptionBool},
+			Option{"packet size", OptionInt},
+			Option{"protocol", OptionString},
+			Option{"serverspn", OptionString},
+			Option{"tlsmin", OptionString},
+			Option{"trustservercertificate", OptionBool},
+			Option{"workstation id", OptionString},
+		),
+	},
+	foldCase: map[string]bool{
+		DialectSQLServer: true,
+	},
+	accessors: map[string]accessorOptions{
+		DialectMySQL:     {timeout: "timeout", parseTime: "parseTime", tls: "tls", charset: "charset"},
+		DialectPostgres:  {timeout: "connect_timeout", tls: "sslmode", charset: "client_encoding"},
+		DialectSQLite:    {timeout: "_busy_timeout"},
+		DialectSQLServer: {timeout: "dial timeout", tls: "encrypt"},
+	},
+}
+
+func optionsByName(options ...Option) map[string]Option {
+
+	m := make(map[string]Option, len(options))
+	for _, o := range options {
+		m[o.Name] = o
+	}
+
+	return m
+}
+
+// RegisterOptions adds options to those known by the dialect registered
+// as dialect, replacing options with the same name. This is used to validate
+// options of dialects registered using RegisterDialect, or to allow options
+// such as MySQL system variables.
+func RegisterOptions(dialect string, options ...Option) {
+
+	knownOptions.Lock()
+	defer knownOptions.Unlock()
+
+	dialect = dialectName(dialect)
+
+	known := knownOptions.options[dialect]
+	if known == nil {
+		known = map[string]Option{}
+		knownOptions.options[dialect] = known
+	}
+
+	for _, o := range options {
+		if knownOptions.foldCase[dialect] {
+			o.Name = strings.ToLower(o.Name)
+		}
+		known[o.Name] = o
+	}
+}
+
+// KnownOptions returns the options known by the dialect registered as
+// dialect, sorted by name.
+func KnownOptions(dialect string) []Option {
+
+	knownOptions.RLock()
+	defer knownOptions.RUnlock()
+
+	var options []Option
+	for _, o := range knownOptions.options[dialectName(dialect)] {
+		options = append(options, o)
+	}
+
+	slices.SortFunc(options, func(a, b Option) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return options
+}
+
+// lookupOption returns the option name known by dialect.
+func lookupOption(dialect, name string) (Option, bool) {
+
+	knownOptions.RLock()
+	defer knownOptions.RUnlock()
+
+	if knownOptions.foldCase[dialect] {
+		name = strings.ToLower(name)
+	}
+
+	o, ok := knownOptions.options[dialect][name]
+
+	return o, ok
+}
+
+// suggestOption returns the option known by dialect which name is most
+// likely misspelled as name, or an empty string when there is none.
+func suggestOption(dialect, name string) string {
+
+	var suggestion string
+	// more edits than about a third of the name is not a misspelling
+	best := max(1, len(name)/3) + 1
+
+	for _, o := range KnownOptions(dialect) {
+		if strings.EqualFold(o.Name, name) {
+			return o.Name
+		}
+
+		if d := editDistance(strings.ToLower(o.Name), strings.ToLower(name)); d < best {
+			suggestion, best = o.Name, d
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+// dialectName returns the name of the dialect registered as name, or
+// name itself when there is none.
+func dialectName(name string) string {
+
+	if d, ok := LookupDialect(name); ok {
+		return d.Name()
+	}
+
+	return name
+}
+
+// ValidateOptions checks the options of d against those known by its
+// dialect. The returned error joins an OptionError for each option which
+// is unknown, or has a value which cannot be parsed. Callers which only
+// want to warn about unknown, possibly misspelled, options can check these
+// using errors.Is with ErrUnknownOption.
+//
+// Options are not checked when the dialect has no known options.
+func (d *DataSource) ValidateOptions() error {
+
+	dialect := d.dialect().Name()
+	if len(KnownOptions(dialect)) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(d.Options))
+	for k := range d.Options {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var errs []error
+	for _, k := range keys {
+		o, ok := lookupOption(dialect, k)
+		if !ok {
+			errs = append(errs, &OptionError{
+				Key:        k,
+				Value:      d.Options.Get(k),
+				Suggestion: suggestOption(dialect, k),
+				Err:        ErrUnknownOption,
+			})
+			continue
+		}
+
+		for _, v := range d.Options[k] {
+			if _, err := o.parse(v); err != nil {
+				errs = append(errs, &OptionError{Key: k, Value: v, Err: err})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// accessors returns the options used by the typed accessors for the
+// dialect of d.
+func (d *DataSource) accessors() accessorOptions {
+
+	knownOptions.RLock()
+	defer knownOptions.RUnlock()
+
+	return knownOptions.accessors[d.dialect().Name()]
+}
+
+// Timeout returns the timeout for establishing connections, or zero when it
+// is not set or invalid. The option depends on the dialect, for example,
+// "timeout" for MySQL and "connect_timeout" for PostgreSQL.
+func (d *DataSource) Timeout() time.Duration {
+
+	name := d.accessors().timeout
+	value := d.option(name)
+	if value == "" {
+		return 0
+	}
+
+	o, _ := lookupOption(d.dialect().Name(), name)
+	t, err := o.parse(value)
+	if err != nil {
+		return 0
+	}
+
+	return t
+}
+
+// SetTimeout sets the timeout for establishing connections, removing the
+// option when timeout is zero. Durations are rounded up when the dialect
+// uses whole seconds or milliseconds. It is ignored when the dialect has no
+// such option.
+func (d *DataSource) SetTimeout(timeout time.Duration) {
+
+	name := d.accessors().timeout
+	if name == "" {
+		return
+	}
+
+	if timeout <= 0 {
+		d.Options.Del(d.optionKey(name))
+		return
+	}
+
+	o, _ := lookupOption(d.dialect().Name(), name)
+	d.setOption(name, o.formatDuration(timeout))
+}
+
+// ParseTime returns whether the driver returns DATE and DATETIME values as
+// time.Time. This is the "parseTime" option of MySQL; false is returned
+// when it is not set, invalid, or the dialect has no such option.
+func (d *DataSource) ParseTime() bool {
+
+	name := d.accessors().parseTime
+	if name == "" {
+		return false
+	}
+
+	b, _ := xconv.ParseBool(d.option(name))
+
+	return b
+}
+
+// SetParseTime sets whether the driver returns DATE and DATETIME values as
+// time.Time. It is ignored when the dialect has no such option.
+func (d *DataSource) SetParseTime(parseTime bool) {
+
+	if name := d.accessors().parseTime; name != "" {
+		d.setOption(name, strconv.FormatBool(parseTime))
+	}
+}
+
+// TLSConfigName returns the TLS configuration, which is, for example, the
+// name of the registered TLS configuration for MySQL, or the "sslmode" for
+// PostgreSQL.
+func (d *DataSource) TLSConfigName() string {
+
+	return d.option(d.accessors().tls)
+}
+
+// SetTLSConfigName sets the TLS configuration, removing the option when name
+// is empty. It is ignored when the dialect has no such option.
+func (d *DataSource) SetTLSConfigName(name string) {
+
+	d.setOption(d.accessors().tls, name)
+}
+
+// Charset returns the character set used by the connection, which is
+// "charset" for MySQL, and "client_encoding" for PostgreSQL.
+func (d *DataSource) Charset() string {
+
+	return d.option(d.accessors().charset)
+}
+
+// SetCharset sets the character set used by the connection, removing
+// the option when charset is empty. It is ignored when the dialect has no
+// such option.
+func (d *DataSource) SetCharset(charset string) {
+
+	d.setOption(d.accessors().charset, charset)
+}
+
+// optionKey returns the key of d.Options holding the option name. When the
+// dialect has case-insensitive option names, like ValidateOptions uses
+// them, the key might be written differently, for example, "Dial Timeout"
+// for "dial timeout". When not found, name is returned.
+func (d *DataSource) optionKey(name string) string {
+
+	if _, ok := d.Options[name]; ok || name == "" {
+		return name
+	}
+
+	knownOptions.RLock()
+	fold := knownOptions.foldCase[d.dialect().Name()]
+	knownOptions.RUnlock()
+
+	if fold {
+		for _, k := range slices.Sorted(maps.Keys(d.Options)) {
+			if strings.EqualFold(k, name) {
+				return k
+			}
+		}
+	}
+
+	return name
+}
+
+func (d *DataSource) option(name string) string {
+
+	if name == "" {
+		return ""
+	}
+
+	return d.Options.Get(d.optionKey(name))
+}
+
+func (d *DataSource) setOption(name, value string) {
+
+	if name != "" {
+		name = d.optionKey(name)
+	}
+
+	switch {
+	case name == "":
+		return
+	case value == "":
+		d.Options.Del(name)
+	default:
+		if d.Options == nil {
+			d.Options = map[string][]string{}
+		}
+		d.Options.Set(name, value)
+	}
+}
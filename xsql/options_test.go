@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2026, Geert JM Vanderkelen
+ */
+
+package xsql_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golistic/xgo/xsql"
+	"github.com/golistic/xgo/xt"
+)
+
+func TestDataSource_accessors(t *testing.T) {
+
+	t.Run("mysql", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("u:p@tcp(localhost)/app?parseTime=yes&timeout=1m30s&tls=custom&charset=utf8mb4")
+		xt.OK(t, err)
+		xt.Eq(t, 90*time.Second, ds.Timeout())
+		xt.Assert(t, ds.ParseTime())
+		xt.Eq(t, "custom", ds.TLSConfigName())
+		xt.Eq(t, "utf8mb4", ds.Charset())
+
+		ds.SetTimeout(5 * time.Second)
+		ds.SetParseTime(false)
+		ds.SetTLSConfigName("")
+		ds.SetCharset("latin1")
+		xt.Eq(t, "u:p@tcp(localhost)/app?parseTime=false&timeout=5s&charset=latin1", ds.Format())
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("postgres://u@localhost/app?connect_timeout=10&sslmode=require")
+		xt.OK(t, err)
+		xt.Eq(t, 10*time.Second, ds.Timeout())
+		xt.Assert(t, !ds.ParseTime())
+		xt.Eq(t, "require", ds.TLSConfigName())
+
+		ds.SetTimeout(1500 * time.Millisecond)
+		ds.SetParseTime(true)
+		ds.SetCharset("UTF8")
+		xt.Eq(t, "postgres://u@localhost/app?connect_timeout=2&sslmode=require&client_encoding=UTF8", ds.Format())
+	})
+
+	t.Run("sqlserver folds case", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("sqlserver://sa@localhost?database=app&Dial+Timeout=5&Encrypt=strict")
+		xt.OK(t, err)
+		xt.OK(t, ds.ValidateOptions())
+		xt.Eq(t, 5*time.Second, ds.Timeout())
+		xt.Eq(t, "strict", ds.TLSConfigName())
+
+		ds.SetTimeout(10 * time.Second)
+		ds.SetTLSConfigName("")
+		xt.Eq(t, "10", ds.Options.Get("Dial Timeout"))
+		xt.Eq(t, "", ds.Options.Get("dial timeout"))
+		xt.Assert(t, !ds.Options.Has("Encrypt"))
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("file:app.db?_busy_timeout=2500")
+		xt.OK(t, err)
+		xt.Eq(t, 2500*time.Millisecond, ds.Timeout())
+
+		ds.SetTimeout(0)
+		xt.Eq(t, "file:app.db", ds.Format())
+	})
+
+	t.Run("invalid or not set", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("u:p@tcp(localhost)/app?parseTime=maybe&timeout=5")
+		xt.OK(t, err)
+		xt.Eq(t, time.Duration(0), ds.Timeout())
+		xt.Assert(t, !ds.ParseTime())
+		xt.Eq(t, "", ds.Charset())
+	})
+
+	t.Run("options not initialized", func(t *testing.T) {
+		ds := &xsql.DataSource{User: "u", Protocol: "tcp", Address: "localhost"}
+		ds.SetParseTime(true)
+		xt.Eq(t, "u@tcp(localhost)/?parseTime=true", ds.Format())
+	})
+}
+
+func TestDataSource_ValidateOptions(t *testing.T) {
+
+	t.Run("valid", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("u:p@tcp(localhost)/app?parseTime=true&timeout=5s&time_zone=%27%2B00%3A00%27")
+		xt.OK(t, err)
+		xt.OK(t, ds.ValidateOptions())
+	})
+
+	t.Run("unknown and invalid", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("u:p@tcp(localhost)/app?parsetime=true&timeOut=5s&readTimeout=5&foo=bar")
+		xt.OK(t, err)
+
+		err = ds.ValidateOptions()
+		xt.KO(t, err)
+		xt.Assert(t, errors.Is(err, xsql.ErrUnknownOption))
+		xt.Eq(t, "unknown option 'foo'\n"+
+			"unknown option 'parsetime' (did you mean 'parseTime'?)\n"+
+			"invalid value '5' for option 'readTimeout' (time: missing unit in duration \"5\")\n"+
+			"unknown option 'timeOut' (did you mean 'timeout'?)", err.Error())
+
+		var optErr *xsql.OptionError
+		xt.Assert(t, errors.As(err, &optErr))
+		xt.Eq(t, "foo", optErr.Key)
+	})
+
+	t.Run("case-insensitive options", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("sqlserver://sa@localhost?database=app&TrustServerCertificate=true&encrytp=true")
+		xt.OK(t, err)
+		xt.Eq(t, "unknown option 'encrytp' (did you mean 'encrypt'?)", ds.ValidateOptions().Error())
+	})
+
+	t.Run("registered options", func(t *testing.T) {
+		ds, err := xsql.ParseDSN("u:p@tcp(localhost)/app?sql_select_limit=10")
+		xt.OK(t, err)
+		xt.KO(t, ds.ValidateOptions())
+
+		xsql.RegisterOptions("mysql", xsql.Option{Name: "sql_select_limit", Kind: xsql.OptionInt})
+		xt.OK(t, ds.ValidateOptions())
+	})
+}